@@ -0,0 +1,183 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+// outcome of one file in a -separate-prs run 📊
+type batchResult struct {
+	file      string
+	branch    string
+	prURL     string
+	unchanged bool // the copy matched what's committed, so nothing was done
+	err       error
+}
+
+// creates a branch, commit and pr for each selected file in turn, starting
+// every one from the branch that was checked out when elf-owl started 🔁
+// keeps going past failures and returns false if any file failed, along
+// with the urls of the prs that were opened
+func runSeparatePRs(opts gitOptions, copyOpts copyOptions, selected []searchFile) ([]string, bool) {
+	dir := opts.targetDir
+	baseBranch, err := batchBase(dir, "-separate-prs")
+	if err != nil {
+		logError("error: %v", err)
+		return nil, false
+	}
+
+	// one browser tab per pr would be a lot, the summary lists the urls
+	opts.skipBrowse = true
+
+	var results []batchResult
+	for i, file := range selected {
+		logInfo("[%d/%d] %s", i+1, len(selected), file.display)
+
+		results = append(results, batchFile(opts, copyOpts, file))
+
+		// back to the base branch for the next file
+		if err := runCommand(dir, "git", "checkout", "-f", baseBranch); err != nil {
+			logError("error returning to %s, stopping: %v", baseBranch, err)
+			break
+		}
+	}
+
+	printBatchSummary(results)
+
+	ok := len(results) == len(selected)
+	var urls []string
+	for _, result := range results {
+		if result.err != nil {
+			ok = false
+		}
+		if result.prURL != "" {
+			urls = append(urls, result.prURL)
+		}
+	}
+	return urls, ok
+}
+
+// names the branch for one file and opens its pr, logging what went wrong
+func batchFile(opts gitOptions, copyOpts copyOptions, file searchFile) batchResult {
+	result := batchResult{file: file.display}
+	result.branch, result.err = branchNameFor(opts.branchCmd, opts.targetDir, file.rel)
+	if result.err == nil {
+		result.prURL, result.err = separatePR(opts, copyOpts, file, result.branch)
+	}
+	if errors.Is(result.err, errNoChanges) {
+		logInfo("no changes to commit — skipping PR")
+		result.err = nil
+		result.unchanged = true
+	}
+	if result.err != nil {
+		logError("error: %v", result.err)
+	}
+	return result
+}
+
+// checks that dir is clean, since each pr has to start from a clean base or
+// earlier leftovers leak in, and returns what to check out between files
+func batchBase(dir, mode string) (string, error) {
+	status, err := commandOutput(dir, "git", "status", "--porcelain")
+	if err != nil {
+		return "", fmt.Errorf("failed to check the working tree: %w", err)
+	}
+	if status != "" {
+		return "", fmt.Errorf("%s needs a clean working tree in %s", mode, dir)
+	}
+
+	baseBranch, err := commandOutput(dir, "git", "rev-parse", "--abbrev-ref", "HEAD")
+	if err == nil && baseBranch == "HEAD" {
+		// detached, e.g. in a -worktree, so come back to the commit itself
+		baseBranch, err = commandOutput(dir, "git", "rev-parse", "HEAD")
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to find the current branch: %w", err)
+	}
+	return baseBranch, nil
+}
+
+// copies one file and opens its pr on a fresh branch
+func separatePR(opts gitOptions, copyOpts copyOptions, file searchFile, branch string) (string, error) {
+	if copyOpts.expandVars != nil {
+		copyOpts.expandVars = withBranch(copyOpts.expandVars, branch)
+	}
+	destPaths, err := copySelected(copyOpts, []searchFile{file})
+	if err != nil {
+		return "", fmt.Errorf("failed to copy file: %w", err)
+	}
+
+	// remember which files are new, so a failed run can clean them up
+	var created []string
+	for _, destPath := range destPaths {
+		if _, err := commandOutput(opts.targetDir, "git", "ls-files", "--error-unmatch", destPath); err != nil {
+			created = append(created, destPath)
+		}
+	}
+
+	opts.branchName = branch
+	opts.files = destPaths
+	if opts.labelSegment > 0 {
+		opts.labels = pathLabels([]searchFile{file}, opts.labelSegment)
+	}
+	prURL, err := gitOperations(opts)
+	if err != nil {
+		// a forced checkout of the base leaves untracked copies behind 🧹
+		for _, path := range created {
+			os.Remove(path)
+		}
+		return "", err
+	}
+
+	// gh prints the pr url as the last line of its output
+	return lastLine(prURL), nil
+}
+
+// prints one line per file with its branch and pr url or error 📋
+// -q only hides it when everything went through, since the errors point
+// at it; it goes to stderr then
+func printBatchSummary(results []batchResult) {
+	failed := false
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "FILE\tBRANCH\tRESULT")
+	for _, result := range results {
+		outcome := result.prURL
+		if outcome == "" {
+			outcome = "committed, not pushed"
+		}
+		if result.unchanged {
+			outcome = "no changes, skipped"
+		}
+		if result.err != nil {
+			outcome = colorize(ansiRed, "failed: "+firstLine(result.err.Error()))
+			failed = true
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", result.file, result.branch, outcome)
+	}
+	w.Flush()
+	if failed && currentLevel < levelInfo {
+		fmt.Fprintln(os.Stderr, strings.TrimRight(b.String(), "\n"))
+		return
+	}
+	logInfo("\n%s", strings.TrimRight(b.String(), "\n"))
+}
+
+// returns the last line of s
+func lastLine(s string) string {
+	if i := strings.LastIndex(s, "\n"); i >= 0 {
+		return s[i+1:]
+	}
+	return s
+}
+
+// returns the first line of s
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// clipboard tools to try, in order, and the arguments that make each read
+// stdin into the clipboard 📋
+var clipboardCommands = [][]string{
+	{"pbcopy"},
+	{"wl-copy"},
+	{"xclip", "-selection", "clipboard"},
+	{"xsel", "--clipboard", "--input"},
+	{"clip.exe"},
+}
+
+// copies text to the system clipboard with the first tool found in path
+func copyToClipboard(text string) error {
+	for _, candidate := range clipboardCommands {
+		// wl-copy only works inside a wayland session
+		if candidate[0] == "wl-copy" && os.Getenv("WAYLAND_DISPLAY") == "" {
+			continue
+		}
+		if len(missingCommands(candidate[:1])) > 0 {
+			continue
+		}
+
+		logCommand("", nil, candidate[0], candidate[1:]...)
+		cmd := exec.CommandContext(runCtx, candidate[0], candidate[1:]...)
+		cmd.Stdin = strings.NewReader(text)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			if msg := strings.TrimSpace(string(out)); msg != "" {
+				return fmt.Errorf("%s failed: %v: %s", candidate[0], err, msg)
+			}
+			return fmt.Errorf("%s failed: %w", candidate[0], err)
+		}
+		return nil
+	}
+	return fmt.Errorf("no clipboard tool found (install pbcopy, wl-copy, xclip or xsel)")
+}
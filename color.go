@@ -0,0 +1,37 @@
+package main
+
+import "os"
+
+// ansi escape codes for the few colors we use 🎨
+const (
+	ansiReset  = "\033[0m"
+	ansiRed    = "\033[31m"
+	ansiGreen  = "\033[32m"
+	ansiYellow = "\033[33m"
+)
+
+// whether output should be colorized, decided once flags are parsed
+var colorEnabled = false
+
+// decides whether to colorize: only on a terminal, and never with
+// NO_COLOR, -no-color or -q 🖍️
+func setupColor(noColor bool) {
+	if noColor || currentLevel == levelQuiet {
+		colorEnabled = false
+		return
+	}
+	if _, ok := os.LookupEnv("NO_COLOR"); ok {
+		colorEnabled = false
+		return
+	}
+	info, err := os.Stdout.Stat()
+	colorEnabled = err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// wraps s in the given color when color is enabled
+func colorize(color, s string) string {
+	if !colorEnabled {
+		return s
+	}
+	return color + s + ansiReset
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// flags that are left out of the usage and completion output 🙈
+var hiddenFlags = map[string]bool{
+	"completion": true,
+}
+
+// flags whose values should complete as directories 📁
+var dirFlags = map[string]bool{
+	"search": true,
+	"target": true,
+}
+
+// flags whose values should complete as files 📄
+var fileFlags = map[string]bool{
+	"body-file":        true,
+	"commit-body-file": true,
+}
+
+// fixed sets of values to complete for enum-like flags 🎯
+var flagValues = map[string][]string{
+	"merge-method":    {"squash", "merge", "rebase"},
+	"on-exists":       {"fail", "suffix", "force", "switch"},
+	"update-strategy": {"rebase", "merge"},
+}
+
+// reports whether a flag takes no value (e.g. -version)
+func isBoolFlag(f *flag.Flag) bool {
+	bf, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && bf.IsBoolFlag()
+}
+
+// returns all visible flags sorted by name
+func visibleFlags() []*flag.Flag {
+	var flags []*flag.Flag
+	flag.VisitAll(func(f *flag.Flag) {
+		if !hiddenFlags[f.Name] {
+			flags = append(flags, f)
+		}
+	})
+	sort.Slice(flags, func(i, j int) bool { return flags[i].Name < flags[j].Name })
+	return flags
+}
+
+// writes a completion script for the given shell to w 🐚
+func generateCompletion(shell string, w io.Writer) error {
+	switch shell {
+	case "bash":
+		return bashCompletion(w)
+	case "zsh":
+		return zshCompletion(w)
+	case "fish":
+		return fishCompletion(w)
+	default:
+		return fmt.Errorf("unsupported shell '%s' (want bash, zsh or fish)", shell)
+	}
+}
+
+func bashCompletion(w io.Writer) error {
+	var names, dirs, files, values []string
+	var enums []*flag.Flag
+	for _, f := range visibleFlags() {
+		names = append(names, "-"+f.Name)
+		switch {
+		case flagValues[f.Name] != nil:
+			enums = append(enums, f)
+		case dirFlags[f.Name]:
+			dirs = append(dirs, "-"+f.Name)
+		case fileFlags[f.Name]:
+			files = append(files, "-"+f.Name)
+		case !isBoolFlag(f):
+			values = append(values, "-"+f.Name)
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("# bash completion for elf-owl 🦉\n")
+	b.WriteString("_elf_owl() {\n")
+	b.WriteString("    local cur=\"${COMP_WORDS[COMP_CWORD]}\"\n")
+	b.WriteString("    local prev=\"${COMP_WORDS[COMP_CWORD-1]}\"\n")
+	b.WriteString("    case \"$prev\" in\n")
+	for _, f := range enums {
+		fmt.Fprintf(&b, "        -%s)\n", f.Name)
+		fmt.Fprintf(&b, "            COMPREPLY=( $(compgen -W \"%s\" -- \"$cur\") )\n", strings.Join(flagValues[f.Name], " "))
+		b.WriteString("            return ;;\n")
+	}
+	if len(dirs) > 0 {
+		fmt.Fprintf(&b, "        %s)\n", strings.Join(dirs, "|"))
+		b.WriteString("            COMPREPLY=( $(compgen -d -- \"$cur\") )\n")
+		b.WriteString("            return ;;\n")
+	}
+	if len(files) > 0 {
+		fmt.Fprintf(&b, "        %s)\n", strings.Join(files, "|"))
+		b.WriteString("            COMPREPLY=( $(compgen -f -- \"$cur\") )\n")
+		b.WriteString("            return ;;\n")
+	}
+	if len(values) > 0 {
+		fmt.Fprintf(&b, "        %s)\n", strings.Join(values, "|"))
+		b.WriteString("            COMPREPLY=()\n")
+		b.WriteString("            return ;;\n")
+	}
+	b.WriteString("    esac\n")
+	fmt.Fprintf(&b, "    COMPREPLY=( $(compgen -W \"%s\" -- \"$cur\") )\n", strings.Join(names, " "))
+	b.WriteString("}\n")
+	b.WriteString("complete -F _elf_owl elf-owl\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func zshCompletion(w io.Writer) error {
+	// escapes characters that are special inside an _arguments spec
+	escape := strings.NewReplacer("'", "'\\''", "[", "\\[", "]", "\\]", ":", "\\:")
+
+	var b strings.Builder
+	b.WriteString("#compdef elf-owl\n")
+	b.WriteString("# zsh completion for elf-owl 🦉\n")
+	b.WriteString("_arguments \\\n")
+	for _, f := range visibleFlags() {
+		spec := fmt.Sprintf("-%s[%s]", f.Name, escape.Replace(f.Usage))
+		switch {
+		case flagValues[f.Name] != nil:
+			spec += fmt.Sprintf(":%s:(%s)", f.Name, strings.Join(flagValues[f.Name], " "))
+		case dirFlags[f.Name]:
+			spec += ":directory:_files -/"
+		case fileFlags[f.Name]:
+			spec += ":file:_files"
+		case !isBoolFlag(f):
+			spec += ":" + f.Name + ": "
+		}
+		fmt.Fprintf(&b, "  '%s' \\\n", spec)
+	}
+	b.WriteString("  && return 0\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func fishCompletion(w io.Writer) error {
+	escape := strings.NewReplacer("\\", "\\\\", "'", "\\'")
+
+	var b strings.Builder
+	b.WriteString("# fish completion for elf-owl 🦉\n")
+	for _, f := range visibleFlags() {
+		line := fmt.Sprintf("complete -c elf-owl -o %s -d '%s'", f.Name, escape.Replace(f.Usage))
+		switch {
+		case flagValues[f.Name] != nil:
+			line += fmt.Sprintf(" -x -a '%s'", strings.Join(flagValues[f.Name], " "))
+		case dirFlags[f.Name]:
+			line += " -x -a '(__fish_complete_directories)'"
+		case fileFlags[f.Name]:
+			line += " -r -F"
+		case !isBoolFlag(f):
+			line += " -x"
+		}
+		b.WriteString(line + "\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// name of the repo-local config file 📄
+const localConfigName = ".elfowl.yaml"
+
+// prefix for environment variable overrides 🌍
+const envPrefix = "ELFOWL_"
+
+// flags that only make sense on the command line
+var configIgnoredFlags = map[string]bool{
+	"completion": true,
+	"version":    true,
+}
+
+// returns the config files to load, lowest precedence first ⚙️
+func configPaths() []string {
+	var paths []string
+
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		if home, err := os.UserHomeDir(); err == nil {
+			configHome = filepath.Join(home, ".config")
+		}
+	}
+	if configHome != "" {
+		paths = append(paths, filepath.Join(configHome, "elf-owl", "config.yaml"))
+	}
+
+	// repo-local config wins over the user config
+	paths = append(paths, localConfigName)
+	return paths
+}
+
+// parses a flat "key: value" yaml file into a map
+func parseConfigFile(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("%s:%d: expected 'key: value'", path, lineNum)
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+
+		// strip surrounding quotes, otherwise drop trailing comments
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		} else if i := strings.Index(value, " #"); i >= 0 {
+			value = strings.TrimSpace(value[:i])
+		}
+
+		values[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	return values, nil
+}
+
+// applies config file values as flag defaults, before flag.Parse 🔧
+func loadConfig() error {
+	for _, path := range configPaths() {
+		values, err := parseConfigFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		for key, value := range values {
+			if configIgnoredFlags[key] || flag.Lookup(key) == nil {
+				return fmt.Errorf("%s: unknown option '%s'", path, key)
+			}
+			if err := flag.Set(key, value); err != nil {
+				return fmt.Errorf("%s: invalid value for '%s': %w", path, key, err)
+			}
+		}
+		startFlagLayer()
+	}
+	return nil
+}
+
+// lets list flags from the next layer (env, command line) replace values
+// from this one instead of adding to them
+func startFlagLayer() {
+	flag.VisitAll(func(f *flag.Flag) {
+		if layered, ok := f.Value.(interface{ newLayer() }); ok {
+			layered.newLayer()
+		}
+	})
+}
+
+// returns the environment variable that overrides a flag, e.g. ELFOWL_BODY_FILE
+func envVarName(flagName string) string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+// applies ELFOWL_* environment variables as flag defaults, before flag.Parse 🌍
+func loadEnv() error {
+	var err error
+	flag.VisitAll(func(f *flag.Flag) {
+		if err != nil || configIgnoredFlags[f.Name] {
+			return
+		}
+		name := envVarName(f.Name)
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			return
+		}
+		if setErr := flag.Set(f.Name, value); setErr != nil {
+			err = fmt.Errorf("invalid value for %s: %v", name, setErr)
+		}
+	})
+	startFlagLayer()
+	return err
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"errors"
+)
+
+// exit codes, so scripts can tell why a run failed 🚦
+const (
+	exitFailure        = 1   // anything not listed below
+	exitMissingCommand = 2   // a required command (fzf, git, gh) isn't installed
+	exitGitFailure     = 3   // a git or gh step failed
+	exitTimeout        = 124 // -command-timeout or -total-timeout ran out, like timeout(1)
+	exitCancelled      = 130 // the user backed out, like a shell's ctrl-c
+)
+
+// the user cancelled, e.g. escaped out of fzf or declined -preview-diff
+var errCancelled = errors.New("cancelled")
+
+// an error carrying the exit code main should use for it
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitError) Unwrap() error {
+	return e.err
+}
+
+// tags err with an exit code, keeping it nil when err is nil
+func withExitCode(code int, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &exitError{code: code, err: err}
+}
+
+// picks the exit code for an error returned by run 🔢
+// cancellation and timeouts win over an outer code so e.g. a git step
+// that timed out still exits 124
+func exitCodeFor(err error) int {
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, errCancelled):
+		return exitCancelled
+	case errors.Is(err, errCommandTimeout), errors.Is(err, errTotalTimeout):
+		return exitTimeout
+	}
+	var e *exitError
+	if errors.As(err, &e) {
+		return e.code
+	}
+	return exitFailure
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// a {{NAME}} placeholder (spaces inside the braces allowed), the start of
+// one, and a valid NAME
+var (
+	placeholderRe      = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+	placeholderStartRe = regexp.MustCompile(`\{\{\s*[A-Za-z_][A-Za-z0-9_]*`)
+	varNameRe          = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// a flag.Value collecting -var key=value pairs 🔤
+type varFlags struct {
+	values  map[string]string
+	replace bool // the next Set starts afresh, see stringList
+}
+
+func (v *varFlags) String() string {
+	var pairs []string
+	for key, value := range v.values {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (v *varFlags) Set(s string) error {
+	key, value, ok := strings.Cut(s, "=")
+	key = strings.TrimSpace(key)
+	if !ok || !varNameRe.MatchString(key) {
+		return fmt.Errorf("want key=value with a key of letters, digits and _, got '%s'", s)
+	}
+	if v.replace || v.values == nil {
+		v.values = make(map[string]string)
+		v.replace = false
+	}
+	v.values[key] = value
+	return nil
+}
+
+func (v *varFlags) newLayer() {
+	v.replace = true
+}
+
+// the variables every expansion gets, before -var overrides 📅
+func defaultVars(author string) map[string]string {
+	return map[string]string{
+		"DATE":   time.Now().Format("2006-01-02"),
+		"USER":   os.Getenv("USER"),
+		"AUTHOR": author,
+	}
+}
+
+// returns vars plus BRANCH, leaving vars itself alone
+func withBranch(vars map[string]string, branch string) map[string]string {
+	out := make(map[string]string, len(vars)+1)
+	out["BRANCH"] = branch
+	for key, value := range vars {
+		out[key] = value
+	}
+	return out
+}
+
+// replaces every {{NAME}} whose name is in vars, returning the names it
+// left alone; anything else in braces, like a helm {{ .Values.name }},
+// passes through untouched ✨
+// a {{NAME its line doesn't close before the next {{ fails, since that's
+// a typo rather than someone else's template
+func expandPlaceholders(content string, vars map[string]string) (string, []string, error) {
+	for _, loc := range placeholderStartRe.FindAllStringIndex(content, -1) {
+		rest, _, _ := strings.Cut(content[loc[1]:], "\n")
+		rest, _, _ = strings.Cut(rest, "{{")
+		if !strings.Contains(rest, "}}") {
+			snippet, _, _ := strings.Cut(content[loc[0]:], "\n")
+			return "", nil, fmt.Errorf("malformed placeholder near '%s'", snippet)
+		}
+	}
+
+	var unknown []string
+	expanded := placeholderRe.ReplaceAllStringFunc(content, func(match string) string {
+		name := placeholderRe.FindStringSubmatch(match)[1]
+		value, ok := vars[name]
+		if !ok {
+			if !slices.Contains(unknown, name) {
+				unknown = append(unknown, name)
+			}
+			return match
+		}
+		return value
+	})
+	return expanded, unknown, nil
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestExpandPlaceholders(t *testing.T) {
+	vars := map[string]string{"DATE": "2026-10-14", "USER": "owl"}
+	tests := []struct {
+		content     string
+		want        string
+		wantUnknown []string
+	}{
+		{"on {{DATE}} by {{ USER }}", "on 2026-10-14 by owl", nil},
+		{"name: {{ .Values.name }}", "name: {{ .Values.name }}", nil},
+		{"{{lower}} {{DATE}} {{lower}}", "{{lower}} 2026-10-14 {{lower}}", []string{"lower"}},
+		{"{{ include \"x\" . }} {{ if .Values.on }}", "{{ include \"x\" . }} {{ if .Values.on }}", nil},
+		{"}} {{}} {{{DATE}}}", "}} {{}} {2026-10-14}", nil},
+	}
+	for _, tt := range tests {
+		got, unknown, err := expandPlaceholders(tt.content, vars)
+		if err != nil || got != tt.want || !slices.Equal(unknown, tt.wantUnknown) {
+			t.Errorf("expandPlaceholders(%q) = %q, %v, %v, want %q, %v", tt.content, got, unknown, err, tt.want, tt.wantUnknown)
+		}
+	}
+}
+
+func TestExpandPlaceholdersMalformed(t *testing.T) {
+	for _, content := range []string{"{{ DATE", "on {{DATE }\n", "{{USER and {{DATE}}", "ok\n{{ DATE\n}}"} {
+		if _, _, err := expandPlaceholders(content, map[string]string{"DATE": "today", "USER": "owl"}); err == nil || !strings.Contains(err.Error(), "malformed placeholder") {
+			t.Errorf("expandPlaceholders(%q) error = %v, want a malformed placeholder", content, err)
+		}
+	}
+}
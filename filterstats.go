@@ -0,0 +1,84 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// the filters, in the order they're applied and reported
+var filterNames = []string{"since", "size", "pattern"}
+
+// counts how many files each filter removed, safe for concurrent walks 📊
+type filterStats struct {
+	mu       sync.Mutex
+	found    int
+	filtered map[string]int
+}
+
+// counts a file the walk found, before any filter
+func (s *filterStats) addFound() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.found++
+}
+
+// counts a file the named filter removed
+func (s *filterStats) addFiltered(filter string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.filtered == nil {
+		s.filtered = make(map[string]int)
+	}
+	s.filtered[filter]++
+}
+
+// e.g. "320 files found, 295 filtered (12 by since, 283 by size), 25 candidates"
+func (s *filterStats) summary() string {
+	total := 0
+	var parts []string
+	for _, name := range filterNames {
+		if n := s.filtered[name]; n > 0 {
+			total += n
+			parts = append(parts, fmt.Sprintf("%d by %s", n, name))
+		}
+	}
+	if total == 0 {
+		return fmt.Sprintf("%d files found, none filtered", s.found)
+	}
+	return fmt.Sprintf("%d files found, %d filtered (%s), %d candidates",
+		s.found, total, strings.Join(parts, ", "), s.found-total)
+}
+
+// returned by a walk that hit -max-files, so it isn't taken for a real
+// walk error
+var errTooManyFiles = errors.New("too many files")
+
+// stops a walk once more files than max pass the filters, counting across
+// every root and goroutine 🚧
+type fileLimit struct {
+	mu    sync.Mutex
+	max   int
+	count int
+}
+
+// counts one kept file, failing with errTooManyFiles once over the max
+func (l *fileLimit) add() error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.count++
+	if l.count > l.max {
+		return errTooManyFiles
+	}
+	return nil
+}
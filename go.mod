@@ -2,4 +2,8 @@ module github.com/clholm/elf-owl
 
 go 1.22.0
 
-require golang.org/x/exp v0.0.0-20241210194714-1829a127f884 // indirect
+require (
+	github.com/fsnotify/fsnotify v1.8.0
+	golang.org/x/exp v0.0.0-20241210194714-1829a127f884
+	golang.org/x/sys v0.28.0
+)
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// splits a command line into arguments the way a posix shell would,
+// honoring single quotes, double quotes and backslash escapes ✂️
+func splitArgs(s string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	inArg := false
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		case c == '\\':
+			inArg = true
+			if i+1 < len(s) {
+				i++
+				current.WriteByte(s[i])
+			}
+		case c == '\'':
+			inArg = true
+			end := strings.IndexByte(s[i+1:], '\'')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated single quote in %q", s)
+			}
+			current.WriteString(s[i+1 : i+1+end])
+			i += end + 1
+		case c == '"':
+			inArg = true
+			closed := false
+			for i++; i < len(s); i++ {
+				if s[i] == '"' {
+					closed = true
+					break
+				}
+				// inside double quotes only these can be escaped
+				if s[i] == '\\' && i+1 < len(s) && strings.IndexByte("\"\\$`", s[i+1]) >= 0 {
+					i++
+				}
+				current.WriteByte(s[i])
+			}
+			if !closed {
+				return nil, fmt.Errorf("unterminated double quote in %q", s)
+			}
+		default:
+			inArg = true
+			current.WriteByte(c)
+		}
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
+
+// runs a post-copy hook with {file} replaced by the copied file's path,
+// from the directory the file was copied into 🪝
+func runPostCopyHook(hook []string, dir, file string) error {
+	args := make([]string, len(hook))
+	for i, arg := range hook {
+		args[i] = strings.ReplaceAll(arg, "{file}", file)
+	}
+	if err := runCommand(dir, args[0], args[1:]...); err != nil {
+		return fmt.Errorf("post-copy hook failed for %s: %w", file, err)
+	}
+	return nil
+}
+
+// runs the pre-pr hook from dir, telling it what is about to become a pr
+// through ELFOWL_HOOK_BRANCH, ELFOWL_HOOK_FILE and ELFOWL_HOOK_FILES 🪝
+func runPrePRHook(hook []string, dir, branch string, files []string) error {
+	env := []string{
+		"ELFOWL_HOOK_BRANCH=" + branch,
+		"ELFOWL_HOOK_FILES=" + strings.Join(files, "\n"),
+	}
+	if len(files) > 0 {
+		env = append(env, "ELFOWL_HOOK_FILE="+files[0])
+	}
+	if err := runCommandEnv(dir, env, hook[0], hook[1:]...); err != nil {
+		return fmt.Errorf("pre-pr hook failed: %w", err)
+	}
+	return nil
+}
+
+// asks a -branch-cmd for the branch name of file, passing {file} and
+// {date} in its arguments and ELFOWL_HOOK_FILE / ELFOWL_HOOK_DATE in its
+// environment; the last line it prints is the name, kept as is once git
+// accepts it as a branch name 🌿
+func runBranchCommand(hook []string, dir, file string) (string, error) {
+	date := time.Now().Format("06-01-02")
+	args := make([]string, len(hook))
+	for i, arg := range hook {
+		args[i] = strings.NewReplacer("{file}", file, "{date}", date).Replace(arg)
+	}
+	env := []string{"ELFOWL_HOOK_FILE=" + file, "ELFOWL_HOOK_DATE=" + date}
+
+	out, err := execCommand(dir, env, false, args[0], args[1:]...)
+	if err != nil {
+		return "", fmt.Errorf("branch command failed for %s: %w", file, err)
+	}
+	lines := strings.Split(out, "\n")
+	name := strings.TrimSpace(lines[len(lines)-1])
+	if name == "" {
+		return "", fmt.Errorf("branch command printed no branch name for %s", file)
+	}
+	valid, err := checkBranchName(dir, name)
+	if err != nil {
+		return "", fmt.Errorf("branch command for %s printed %w", file, err)
+	}
+	return valid, nil
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// github rejects labels longer than this
+const maxLabelLength = 50
+
+// turns a directory name into a github label: letters, digits, spaces and
+// - _ . are kept, anything else (commas would split gh's --label) becomes
+// a dash 🏷️
+func sanitizeLabel(name string) string {
+	label := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune(" -_.", r) {
+			return r
+		}
+		return '-'
+	}, name)
+	label = strings.Trim(strings.Join(strings.Fields(label), " "), " -")
+	if runes := []rune(label); len(runes) > maxLabelLength {
+		label = strings.TrimRight(string(runes[:maxLabelLength]), " -")
+	}
+	return label
+}
+
+// returns the label for a relative path: its segment-th directory,
+// counting from 1, or "" when the path isn't that deep
+func pathLabel(rel string, segment int) string {
+	dirs := strings.Split(filepath.ToSlash(filepath.Dir(rel)), "/")
+	if dirs[0] == "." || segment < 1 || segment > len(dirs) {
+		return ""
+	}
+	return sanitizeLabel(dirs[segment-1])
+}
+
+// returns the -label-segment to use, 0 when -label-from-path is off
+func labelSegmentFor(fromPath bool, segment int) int {
+	if !fromPath {
+		return 0
+	}
+	return segment
+}
+
+// collects the distinct labels of the files, in order, logging the ones
+// too shallow to have one
+func pathLabels(files []searchFile, segment int) []string {
+	var labels []string
+	for _, file := range files {
+		label := pathLabel(file.rel, segment)
+		if label == "" {
+			logVerbose("no label for %s, it has no directory %d", file.rel, segment)
+			continue
+		}
+		if !slices.Contains(labels, label) {
+			labels = append(labels, label)
+		}
+	}
+	return labels
+}
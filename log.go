@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// how chatty elf-owl is 📢
+type logLevel int
+
+const (
+	levelQuiet   logLevel = iota // only errors
+	levelInfo                    // the usual progress messages
+	levelVerbose                 // also commands and resolved paths
+)
+
+var currentLevel = levelInfo
+
+// prints an error in red to stderr, regardless of level, so it can't end
+// up in piped output like -stdout's ❌
+func logError(format string, args ...any) {
+	fmt.Fprintln(os.Stderr, colorize(ansiRed, fmt.Sprintf(format, args...)))
+}
+
+// prints a success message in green unless quiet ✅
+func logSuccess(format string, args ...any) {
+	if currentLevel >= levelInfo {
+		fmt.Fprintln(os.Stdout, colorize(ansiGreen, fmt.Sprintf(format, args...)))
+	}
+}
+
+// prints a warning in yellow unless quiet ⚠️
+func logWarning(format string, args ...any) {
+	if currentLevel >= levelInfo {
+		fmt.Fprintln(os.Stdout, colorize(ansiYellow, "warning: "+fmt.Sprintf(format, args...)))
+	}
+}
+
+// prints a progress message unless quiet
+func logInfo(format string, args ...any) {
+	if currentLevel >= levelInfo {
+		fmt.Fprintf(os.Stdout, format+"\n", args...)
+	}
+}
+
+// prints a debugging detail in verbose mode only 🔍
+func logVerbose(format string, args ...any) {
+	if currentLevel >= levelVerbose {
+		fmt.Fprintf(os.Stdout, format+"\n", args...)
+	}
+}
+
+// quotes an argument for a posix shell if it needs it
+func shellQuote(arg string) string {
+	if arg == "" {
+		return "''"
+	}
+	safe := true
+	for _, r := range arg {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' ||
+			strings.ContainsRune("_@%+=:,./-", r)) {
+			safe = false
+			break
+		}
+	}
+	if safe {
+		return arg
+	}
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
+
+// formats a command line the way you'd type it 💬
+func formatCommand(name string, args ...string) string {
+	parts := []string{shellQuote(name)}
+	for _, arg := range args {
+		parts = append(parts, shellQuote(arg))
+	}
+	return strings.Join(parts, " ")
+}
+
+// print every command's full, copy-pasteable line to stderr
+var showCommands bool
+
+// logs a command about to run: the reproducible line (directory and extra
+// env included) on stderr with -show-commands, else a short one if verbose 💬
+func logCommand(dir string, env []string, name string, args ...string) {
+	if !showCommands {
+		logVerbose("$ %s", formatCommand(name, args...))
+		return
+	}
+	line := formatCommand(name, args...)
+	if len(env) > 0 {
+		assignments := make([]string, len(env))
+		for i, kv := range env {
+			key, value, _ := strings.Cut(kv, "=")
+			assignments[i] = key + "=" + shellQuote(value)
+		}
+		line = strings.Join(assignments, " ") + " " + line
+	}
+	if dir != "" {
+		line = fmt.Sprintf("(cd %s && %s)", shellQuote(dir), line)
+	}
+	fmt.Fprintln(os.Stderr, "+ "+line)
+}
@@ -14,37 +14,215 @@ package main
 
 import (
 	"bufio"
+	"bytes"
+	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"golang.org/x/exp/rand"
 )
 
-// executes a command and returns any error 🔧
-func runCommand(name string, args ...string) error {
-	cmd := exec.Command(name, args...)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	return cmd.Run()
+// build info, set at build time via
+// -ldflags "-X main.version=... -X main.commit=... -X main.date=..." 🏷️
+var (
+	version = "dev"
+	commit  = "none"
+	date    = "unknown"
+)
+
+// how many files a walk may find before -max-files stops it, far more
+// than fzf is pleasant to use with
+const defaultMaxFiles = 50000
+
+// fzf's height when neither -fzf-height nor FZF_DEFAULT_OPTS set one
+const defaultFzfHeight = "40%"
+
+// above this many files, -all needs -yes so a loose filter can't open a
+// pr with thousands of them 🛑
+const allConfirmThreshold = 100
+
+// copies smaller than this skip -check-space, a full disk is unlikely to
+// be what stops them
+const spaceCheckThreshold = 10 << 20
+
+// where to get each external tool elf-owl shells out to 📦
+var installHints = map[string]string{
+	"fzf": "https://github.com/junegunn/fzf#installation",
+	"git": "https://git-scm.com/downloads",
+	"gh":  "https://cli.github.com",
+}
+
+// path lookups already done, keyed by command name
+var lookPathCache = make(map[string]error)
+
+// returns the commands that can't be found in path, in the given order 🔍
+func missingCommands(cmds []string) []string {
+	var missing []string
+	for _, cmd := range cmds {
+		err, ok := lookPathCache[cmd]
+		if !ok {
+			_, err = exec.LookPath(cmd)
+			lookPathCache[cmd] = err
+		}
+		if err != nil {
+			missing = append(missing, cmd)
+		}
+	}
+	return missing
+}
+
+// executes a command in dir and returns any error 🔧
+// stderr is streamed to the terminal and also included in the error
+// in quiet mode, output is only kept for the error
+func runCommand(dir, name string, args ...string) error {
+	_, err := runCommandOutput(dir, name, args...)
+	return err
+}
+
+// like runCommand, but also returns the command's trimmed stdout 📤
+func runCommandOutput(dir, name string, args ...string) (string, error) {
+	return execCommand(dir, nil, currentLevel != levelQuiet, name, args...)
+}
+
+// like runCommand, with extra KEY=value environment variables 🌱
+func runCommandEnv(dir string, env []string, name string, args ...string) error {
+	_, err := execCommand(dir, env, currentLevel != levelQuiet, name, args...)
+	return err
+}
+
+// runs a command in dir without streaming its output and returns its
+// trimmed stdout, for queries like the current branch 🔎
+func commandOutput(dir, name string, args ...string) (string, error) {
+	return execCommand(dir, nil, false, name, args...)
+}
+
+// executes a command, optionally streaming its output to the terminal
+func execCommand(dir string, env []string, stream bool, name string, args ...string) (string, error) {
+	logCommand(dir, env, name, args...)
+	defer phaseTimes.track(commandPhase(name, args))()
+
+	ctx, cancel := commandContext(false)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = dir
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if stream {
+		cmd.Stdout = io.MultiWriter(os.Stdout, &stdout)
+		cmd.Stderr = io.MultiWriter(os.Stderr, &stderr)
+	}
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return "", timeoutError(ctx, formatCommand(name, args...), err)
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%v: %s", err, msg)
+		}
+		return "", err
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// expands a leading ~ and any $VAR references in a path 🏠
+func expandPath(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, path[1:])
+		}
+	}
+	return os.ExpandEnv(path)
+}
+
+// how fzf is run, from -fzf-height and -fzf-args 🎛️
+type fzfOptions struct {
+	height    string   // --height value, empty for the default
+	extraArgs []string // appended after ours
+}
+
+// settings for findFiles 🔍
+type findOptions struct {
+	followSymlinks bool         // descend into symlinked directories
+	concurrent     bool         // read directories in parallel
+	modifiedAfter  time.Time    // skip files older than this, zero keeps all
+	minSize        int64        // skip smaller files, 0 keeps all
+	maxSize        int64        // skip larger files, 0 keeps all
+	pattern        string       // glob the name or relative path must match, see matchesPattern
+	stats          *filterStats // counts what the filters removed, nil to skip
+	limit          *fileLimit   // stops the walk after too many files, nil for no limit
+}
+
+// reports whether a file passes the filters 🧹
+func (o findOptions) keep(relPath string, info os.FileInfo) bool {
+	o.stats.addFound()
+	if !o.modifiedAfter.IsZero() && info.ModTime().Before(o.modifiedAfter) {
+		o.stats.addFiltered("since")
+		return false
+	}
+	if (o.minSize > 0 && info.Size() < o.minSize) || (o.maxSize > 0 && info.Size() > o.maxSize) {
+		o.stats.addFiltered("size")
+		return false
+	}
+	if o.pattern != "" && !matchesPattern(o.pattern, relPath) {
+		o.stats.addFiltered("pattern")
+		return false
+	}
+	return true
+}
+
+// reports whether a relative path matches a glob, either as a whole
+// (docs/*.md) or by its base name (*.md) 🎯
+func matchesPattern(pattern, relPath string) bool {
+	if ok, _ := filepath.Match(pattern, relPath); ok {
+		return true
+	}
+	ok, _ := filepath.Match(pattern, filepath.Base(relPath))
+	return ok
 }
 
 // finds all files in the given directory recursively 🔍
-func findFiles(dir string) ([]string, error) {
+// symlinks are listed as entries of their own unless followSymlinks is set
+func findFiles(dir string, opts findOptions) ([]string, error) {
+	if opts.concurrent {
+		return findFilesConcurrent(dir, opts)
+	}
+
+	if opts.followSymlinks {
+		var files []string
+		err := walkFollowingSymlinks(dir, "", opts, nil, &files)
+		return files, err
+	}
+
 	var files []string
 	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
-		if !info.IsDir() {
-			// convert to relative path
-			relPath, err := filepath.Rel(dir, path)
-			if err != nil {
+		if info.IsDir() {
+			return nil
+		}
+		// convert to relative path
+		relPath, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if opts.keep(relPath, info) {
+			if err := opts.limit.add(); err != nil {
 				return err
 			}
 			files = append(files, relPath)
@@ -54,20 +232,113 @@ func findFiles(dir string) ([]string, error) {
 	return files, err
 }
 
+// walks dir resolving symlinks, skipping directories already in visited
+// (the chain of directories above this one) so link loops can't recurse
+// forever 🔁
+func walkFollowingSymlinks(dir, relDir string, opts findOptions, visited []os.FileInfo, files *[]string) error {
+	dirInfo, err := os.Stat(dir)
+	if err != nil {
+		return err
+	}
+	for _, seen := range visited {
+		if os.SameFile(seen, dirInfo) {
+			return nil
+		}
+	}
+	visited = append(visited, dirInfo)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		relPath := filepath.Join(relDir, entry.Name())
+
+		info, err := os.Stat(path)
+		if err != nil {
+			// dangling symlinks are still listed and copied as links
+			if entry.Type()&os.ModeSymlink != 0 {
+				if linkInfo, err := entry.Info(); err == nil && opts.keep(relPath, linkInfo) {
+					if err := opts.limit.add(); err != nil {
+						return err
+					}
+					*files = append(*files, relPath)
+				}
+				continue
+			}
+			return err
+		}
+
+		if info.IsDir() {
+			if err := walkFollowingSymlinks(path, relPath, opts, visited, files); err != nil {
+				return err
+			}
+			continue
+		}
+		if opts.keep(relPath, info) {
+			if err := opts.limit.add(); err != nil {
+				return err
+			}
+			*files = append(*files, relPath)
+		}
+	}
+	return nil
+}
+
+// returns the fzf arguments: the window height, then the user's extra
+// args last so they win over ours 🔍
+// with no height given, one set in FZF_DEFAULT_OPTS is left alone and
+// defaultFzfHeight is only the fallback
+func fzfArgs(height string, multi bool, extra []string) []string {
+	var args []string
+	if height == "" && !optsSetHeight(os.Getenv("FZF_DEFAULT_OPTS")) {
+		height = defaultFzfHeight
+	}
+	if height != "" {
+		args = append(args, "--height", height)
+	}
+	if multi {
+		args = append(args, "--multi")
+	}
+	return append(args, extra...)
+}
+
+// reports whether fzf options include a --height
+func optsSetHeight(opts string) bool {
+	args, err := splitArgs(opts)
+	if err != nil {
+		// fzf will complain about them itself
+		return false
+	}
+	for _, arg := range args {
+		if arg == "--height" || strings.HasPrefix(arg, "--height=") {
+			return true
+		}
+	}
+	return false
+}
+
 // presents a fuzzy finder interface using fzf ✨
-func selectFileWithFzf(files []string) (string, error) {
+// in multi mode, tab marks several files and all of them are returned
+func selectFileWithFzf(files []string, multi bool, fzf fzfOptions) ([]string, error) {
+	defer phaseTimes.track("fzf")()
 	// create fzf command
-	cmd := exec.Command("fzf", "--height", "40%")
+	args := fzfArgs(fzf.height, multi, fzf.extraArgs)
+	logCommand("", nil, "fzf", args...)
+	ctx, cancel := commandContext(true)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "fzf", args...)
 
 	// create pipes for stdin and stdout
 	stdin, err := cmd.StdinPipe()
 	if err != nil {
-		return "", fmt.Errorf("failed to create stdin pipe: %v", err)
+		return nil, fmt.Errorf("failed to create stdin pipe: %w", err)
 	}
 
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
-		return "", fmt.Errorf("failed to create stdout pipe: %v", err)
+		return nil, fmt.Errorf("failed to create stdout pipe: %w", err)
 	}
 
 	// set stderr to the terminal
@@ -75,7 +346,7 @@ func selectFileWithFzf(files []string) (string, error) {
 
 	// start fzf 🚀
 	if err := cmd.Start(); err != nil {
-		return "", fmt.Errorf("failed to start fzf: %v", err)
+		return nil, fmt.Errorf("failed to start fzf: %w", err)
 	}
 
 	// write files to fzf
@@ -86,22 +357,82 @@ func selectFileWithFzf(files []string) (string, error) {
 		}
 	}()
 
-	// read selected file
+	// read selected files, one per line
 	scanner := bufio.NewScanner(stdout)
-	var selected string
-	if scanner.Scan() {
-		selected = scanner.Text()
+	var selected []string
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			selected = append(selected, line)
+		}
 	}
 
 	// wait for fzf to exit
 	if err := cmd.Wait(); err != nil {
+		if ctx.Err() != nil {
+			return nil, timeoutError(ctx, "fzf", err)
+		}
 		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 130 {
-			return "", fmt.Errorf("file selection cancelled")
+			return nil, fmt.Errorf("file selection %w", errCancelled)
+		}
+		return nil, fmt.Errorf("fzf failed: %w", err)
+	}
+
+	return selected, nil
+}
+
+// picks the files to copy, every listed one with all or else through fzf ✨
+func selectFiles(files []searchFile, all, multi, yes bool, fzf fzfOptions) ([]searchFile, error) {
+	byDisplay := make(map[string]searchFile, len(files))
+	displays := make([]string, len(files))
+	for i, file := range files {
+		byDisplay[file.display] = file
+		displays[i] = file.display
+	}
+	var selectedDisplays []string
+	if all {
+		// take everything that passed the filters, no fzf 📦
+		if len(displays) > allConfirmThreshold && !yes {
+			return nil, fmt.Errorf("error: -all matched %d files, more than %d, pass -yes to copy them all", len(displays), allConfirmThreshold)
+		}
+		logInfo("selected all %d matching files", len(displays))
+		selectedDisplays = displays
+	} else {
+		var err error
+		selectedDisplays, err = selectFileWithFzf(displays, multi, fzf)
+		if err != nil {
+			return nil, fmt.Errorf("error selecting file: %w", err)
+		}
+	}
+
+	if len(selectedDisplays) == 0 {
+		return nil, fmt.Errorf("no file selected")
+	}
+
+	var selected []searchFile
+	for _, display := range selectedDisplays {
+		file, ok := byDisplay[display]
+		if !ok {
+			return nil, fmt.Errorf("error selecting file: '%s' is not one of the listed files", display)
 		}
-		return "", fmt.Errorf("fzf failed: %v", err)
+		selected = append(selected, file)
 	}
+	return selected, nil
+}
 
-	return strings.TrimSpace(selected), nil
+// writes the contents of each file to stdout, one after the other
+func catFiles(files []searchFile) error {
+	for _, file := range files {
+		f, err := os.Open(filepath.Join(file.root, file.rel))
+		if err != nil {
+			return fmt.Errorf("error reading file: %w", err)
+		}
+		_, err = io.Copy(os.Stdout, f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("error writing %s to stdout: %w", file.display, err)
+		}
+	}
+	return nil
 }
 
 // generates a branch name from filename and date 📅
@@ -109,13 +440,26 @@ func generateBranchName(filename string) string {
 	date := time.Now().Format("06-01-02") // yy-mm-dd format
 	// remove file extension and replace spaces/special chars with dashes
 	base := strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
-	base = strings.Map(func(r rune) rune {
+	return fmt.Sprintf("%s-%s", sanitizeBranchName(base), date)
+}
+
+// names the branch for file with the -branch-cmd if there is one, else
+// with generateBranchName
+func branchNameFor(branchCmd []string, dir, file string) (string, error) {
+	if len(branchCmd) == 0 {
+		return generateBranchName(file), nil
+	}
+	return runBranchCommand(branchCmd, dir, file)
+}
+
+// replaces anything but letters and digits with dashes
+func sanitizeBranchName(name string) string {
+	return strings.Map(func(r rune) rune {
 		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
 			return r
 		}
 		return '-'
-	}, base)
-	return fmt.Sprintf("%s-%s", base, date)
+	}, name)
 }
 
 // returns a random happy emoji and bird emoji 🎲
@@ -127,166 +471,1939 @@ func getRandomEmojis() (string, string) {
 	return happyEmojis[rand.Intn(len(happyEmojis))], birdEmojis[rand.Intn(len(birdEmojis))]
 }
 
-// copies a file from src to dst 📋
-func copyFile(src, dst string) error {
-	sourceFile, err := os.Open(src)
-	if err != nil {
-		return fmt.Errorf("failed to open source file: %v", err)
+// settings for copySelected 📋
+type copyOptions struct {
+	targetDir      string
+	followSymlinks bool              // copy link targets rather than the links themselves
+	rename         string            // destination file name, empty to keep the source name
+	destSubdir     string            // directory under the target to copy into
+	keepParents    int               // how many of the source's parent directories to recreate
+	postCopyHook   []string          // command run on each copied file, {file} is its path
+	textOnly       bool              // refuse to copy files that look binary
+	manifest       *manifest         // records finished copies, nil to skip
+	expandVars     map[string]string // {{VAR}} values for -expand, nil to copy as is
+	checkSpace     bool              // refuse large copies that won't fit in the target
+	preserveXattrs bool              // copy extended attributes onto the copies
+}
+
+// one file's pending copy
+type copyPlan struct {
+	source  string
+	dest    string
+	asLink  bool   // recreate the symlink rather than copy its target
+	content []byte // expanded content to write instead of copying, nil if none
+}
+
+// returns rel cut down to its base name and the parents directories right
+// above it, e.g. security/report.md from a/b/security/report.md with 1 📁
+// a file with fewer parents keeps all it has
+func keptPath(rel string, parents int) string {
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+	if parents+1 < len(parts) {
+		parts = parts[len(parts)-parents-1:]
+	} else if parents+1 > len(parts) {
+		logVerbose("%s has only %d parent directories to keep", rel, len(parts)-1)
 	}
-	defer sourceFile.Close()
+	return filepath.Join(parts...)
+}
 
-	// create destination directory if it doesn't exist
-	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
-		return fmt.Errorf("failed to create destination directory: %v", err)
+// copies the selected files into the target dir and returns their destinations 📋
+func copySelected(opts copyOptions, selected []searchFile) ([]string, error) {
+	defer phaseTimes.track("copy")()
+	// work out every copy before copying anything 📂
+	var plans []copyPlan
+	seenDest := make(map[string]string)
+	for _, file := range selected {
+		selectedFile := file.display
+		sourcePath := filepath.Join(file.root, file.rel)
+		// use the base filename for the destination, under any kept parents
+		destName := keptPath(file.rel, opts.keepParents)
+		if opts.rename != "" {
+			destName = filepath.Join(filepath.Dir(destName), opts.rename)
+		}
+		destPath := filepath.Join(opts.targetDir, opts.destSubdir, destName)
+
+		// selections come from fzf output, so don't trust them to stay put 🛡️
+		if !isWithinDir(file.root, sourcePath) {
+			return nil, fmt.Errorf("selected file '%s' is outside the search directory", selectedFile)
+		}
+		if !isWithinDir(opts.targetDir, destPath) {
+			return nil, fmt.Errorf("destination '%s' is outside the target directory", destPath)
+		}
+
+		if other, ok := seenDest[destPath]; ok {
+			return nil, fmt.Errorf("%s and %s would both be copied to %s", other, selectedFile, destPath)
+		}
+		seenDest[destPath] = selectedFile
+
+		info, err := os.Lstat(sourcePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat source file: %w", err)
+		}
+
+		// links are recreated as links, and so are dangling ones when following
+		asLink := info.Mode()&os.ModeSymlink != 0 && !opts.followSymlinks
+		if info.Mode()&os.ModeSymlink != 0 && opts.followSymlinks {
+			if _, err := os.Stat(sourcePath); err != nil {
+				asLink = true
+			}
+		}
+
+		// refuse binary content when only text is wanted 📝
+		if opts.textOnly && !asLink {
+			binary, err := isBinaryFile(sourcePath)
+			if err != nil {
+				return nil, err
+			}
+			if binary {
+				return nil, fmt.Errorf("%s looks like a binary file, refusing to copy it with -text-only", selectedFile)
+			}
+		}
+
+		plan := copyPlan{source: sourcePath, dest: destPath, asLink: asLink}
+
+		// fill in {{VAR}} placeholders, text files only ✨
+		if opts.expandVars != nil && !asLink {
+			binary, err := isBinaryFile(sourcePath)
+			if err != nil {
+				return nil, err
+			}
+			if !binary {
+				content, err := os.ReadFile(sourcePath)
+				if err != nil {
+					return nil, fmt.Errorf("failed to read source file: %w", err)
+				}
+				expanded, unknown, err := expandPlaceholders(string(content), opts.expandVars)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", selectedFile, err)
+				}
+				for _, name := range unknown {
+					logWarning("%s: left {{%s}} as is, define it with -var %s=... to fill it in", selectedFile, name, name)
+				}
+				plan.content = []byte(expanded)
+			}
+		}
+
+		plans = append(plans, plan)
 	}
 
-	destFile, err := os.Create(dst)
-	if err != nil {
-		return fmt.Errorf("failed to create destination file: %v", err)
+	if opts.checkSpace {
+		if err := checkSpace(opts.targetDir, plans); err != nil {
+			return nil, err
+		}
 	}
-	defer destFile.Close()
 
-	if _, err := io.Copy(destFile, sourceFile); err != nil {
-		return fmt.Errorf("failed to copy file: %v", err)
+	// files this call creates, removed again when a later step fails so a
+	// failed copy (or post-copy hook) leaves nothing behind 🧹
+	var created []string
+	fail := func(err error) ([]string, error) {
+		for _, path := range created {
+			os.Remove(path)
+		}
+		return nil, err
 	}
 
-	return nil
-}
+	var destPaths []string
+	for _, plan := range plans {
+		if _, err := os.Lstat(plan.dest); os.IsNotExist(err) {
+			created = append(created, plan.dest)
+		}
+		if plan.asLink {
+			logInfo("linking %s to %s...", plan.source, plan.dest)
+			if err := copySymlink(plan.source, plan.dest); err != nil {
+				return fail(err)
+			}
+		} else if plan.content != nil {
+			logInfo("expanding %s into %s...", plan.source, plan.dest)
+			if err := writeExpanded(plan.source, plan.dest, plan.content); err != nil {
+				return fail(err)
+			}
+		} else {
+			logInfo("copying %s to %s...", plan.source, plan.dest)
+			if err := copyFile(plan.source, plan.dest); err != nil {
+				return fail(err)
+			}
+		}
+
+		// a missing xattr is worth knowing about, not worth failing over
+		if opts.preserveXattrs && !plan.asLink {
+			if err := copyXattrs(plan.source, plan.dest); err != nil {
+				logWarning("%v", err)
+			}
+		}
 
-// handles all git and github cli operations 🔄
-func gitOperations(branchName, targetDir string) error {
-	// change to target directory
-	if err := os.Chdir(targetDir); err != nil {
-		return fmt.Errorf("failed to change to target directory: %v", err)
+		if len(opts.postCopyHook) > 0 {
+			if err := runPostCopyHook(opts.postCopyHook, opts.targetDir, plan.dest); err != nil {
+				return fail(err)
+			}
+		}
+		destPaths = append(destPaths, plan.dest)
 	}
 
-	// create and checkout new branch 🌿
-	if err := runCommand("git", "checkout", "-b", branchName); err != nil {
-		return fmt.Errorf("failed to create branch: %v", err)
+	// only record once every copy went through
+	if opts.manifest != nil {
+		for _, plan := range plans {
+			if err := opts.manifest.record(plan.source, plan.dest); err != nil {
+				return fail(err)
+			}
+		}
 	}
+	return destPaths, nil
+}
 
-	// stage changes
-	if err := runCommand("git", "add", "."); err != nil {
-		return fmt.Errorf("failed to stage changes: %v", err)
+// refuses copies adding up to more than the target's free space, once they
+// are big enough for a full disk to be a real risk 💾
+func checkSpace(targetDir string, plans []copyPlan) error {
+	var need int64
+	for _, plan := range plans {
+		if plan.asLink {
+			continue
+		}
+		if plan.content != nil {
+			need += int64(len(plan.content))
+			continue
+		}
+		info, err := os.Stat(plan.source)
+		if err != nil {
+			return fmt.Errorf("failed to stat source file: %w", err)
+		}
+		need += info.Size()
+	}
+	if need < spaceCheckThreshold {
+		return nil
 	}
 
-	// commit changes 📝
-	if err := runCommand("git", "commit", "-m", fmt.Sprintf("Add %s", branchName)); err != nil {
-		return fmt.Errorf("failed to commit changes: %v", err)
+	// the destination subdirectory may not exist yet
+	dir := targetDir
+	for {
+		if _, err := os.Stat(dir); err == nil || filepath.Dir(dir) == dir {
+			break
+		}
+		dir = filepath.Dir(dir)
 	}
 
-	// push changes ⬆️
-	if err := runCommand("git", "push", "--set-upstream", "origin", branchName); err != nil {
-		return fmt.Errorf("failed to push changes: %v", err)
+	have, ok, err := freeSpace(dir)
+	if err != nil {
+		return fmt.Errorf("failed to check free space in %s: %w", dir, err)
 	}
+	if !ok {
+		logVerbose("can't check free space on this platform, copying anyway")
+		return nil
+	}
+	if need > have {
+		return fmt.Errorf("not enough space in %s: need %s, have %s", dir, formatSize(need), formatSize(have))
+	}
+	return nil
+}
 
-	// get two random emojis for the new pr
-	happy, bird := getRandomEmojis()
-	// create pr 🎯
-	if err := runCommand("gh", "pr", "create",
-		"--title", branchName,
-		"--body", fmt.Sprintf("New finding! %s%s", happy, bird)); err != nil {
-		return fmt.Errorf("failed to create pr: %v", err)
+// sniffs the first 512 bytes of a file, like http.DetectContentType does,
+// and reports whether it isn't text 🔬
+func isBinaryFile(path string) (bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return false, fmt.Errorf("failed to open source file: %w", err)
 	}
+	defer file.Close()
 
-	// open in browser 🌐
-	if err := runCommand("gh", "browse"); err != nil {
-		return fmt.Errorf("failed to open browser: %v", err)
+	buf := make([]byte, 512)
+	n, err := io.ReadFull(file, buf)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return false, fmt.Errorf("failed to read source file: %w", err)
 	}
 
-	return nil
+	contentType := http.DetectContentType(buf[:n])
+	return !strings.HasPrefix(contentType, "text/"), nil
 }
 
-func main() {
-	// define flags 🚩
-	searchDir := flag.String("search", "", "directory to search for files (required)")
-	targetDir := flag.String("target", ".", "target directory (optional)")
-	branchName := flag.String("branch", "", "branch name (optional) (default <selected file name>)")
+// reports whether path is dir itself or somewhere beneath it 🛡️
+func isWithinDir(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) && !filepath.IsAbs(rel)
+}
 
-	flag.Parse()
+// names path under targetDir rather than the -worktree it was copied into,
+// which is gone by the time anyone reads a summary or manifest
+func inTargetDir(path, workDir, targetDir string) string {
+	if rel, err := filepath.Rel(workDir, path); err == nil && isWithinDir(workDir, path) {
+		return filepath.Join(targetDir, rel)
+	}
+	return path
+}
 
-	// validate required flags
-	if *searchDir == "" {
-		fmt.Println("error: search directory is required")
-		flag.Usage()
-		os.Exit(1)
+// recreates the symlink at src as dst, pointing at the same target 🔗
+func copySymlink(src, dst string) error {
+	linkTarget, err := os.Readlink(src)
+	if err != nil {
+		return fmt.Errorf("failed to read symlink: %w", err)
 	}
 
-	// validate that searchdir exists 🔍
-	if _, err := os.Stat(*searchDir); os.IsNotExist(err) {
-		fmt.Printf("error: search directory '%s' does not exist\n", *searchDir)
-		os.Exit(1)
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return fmt.Errorf("failed to create destination directory: %w", err)
 	}
 
-	// convert paths to absolute ✨
-	absSearchDir, err := filepath.Abs(*searchDir)
-	if err != nil {
-		fmt.Printf("error getting absolute path: %v\n", err)
-		os.Exit(1)
+	// replace whatever is already there, like copyFile does
+	if err := os.Remove(dst); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to replace destination file: %w", err)
 	}
-	absTargetDir, err := filepath.Abs(*targetDir)
+
+	if err := os.Symlink(linkTarget, dst); err != nil {
+		return fmt.Errorf("failed to create symlink: %w", err)
+	}
+	return nil
+}
+
+// copies a file from src to dst 📋
+func copyFile(src, dst string) error {
+	sourceFile, err := os.Open(src)
 	if err != nil {
-		fmt.Printf("error getting absolute path: %v\n", err)
-		os.Exit(1)
+		return fmt.Errorf("failed to open source file: %w", err)
 	}
+	defer sourceFile.Close()
 
-	// verify required commands exist 🛠️
-	requiredCommands := []string{"fzf", "git", "gh"}
-	for _, cmd := range requiredCommands {
-		if _, err := exec.LookPath(cmd); err != nil {
-			fmt.Printf("error: required command '%s' not found in path\n", cmd)
-			os.Exit(1)
-		}
+	// create destination directory if it doesn't exist
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return fmt.Errorf("failed to create destination directory: %w", err)
 	}
 
-	// find all files in search directory
-	files, err := findFiles(absSearchDir)
+	destFile, err := os.Create(dst)
 	if err != nil {
-		fmt.Printf("error finding files: %v\n", err)
-		os.Exit(1)
+		return fmt.Errorf("failed to create destination file: %w", err)
 	}
+	defer destFile.Close()
 
-	if len(files) == 0 {
-		fmt.Printf("no files found in search directory '%s'\n", absSearchDir)
-		os.Exit(1)
+	if _, err := io.Copy(destFile, sourceFile); err != nil {
+		return fmt.Errorf("failed to copy file: %w", err)
 	}
 
-	// select file using fzf ✨
-	selectedFile, err := selectFileWithFzf(files)
+	return nil
+}
+
+// writes the expanded content of src to dst, keeping src's permissions
+func writeExpanded(src, dst string, content []byte) error {
+	info, err := os.Stat(src)
 	if err != nil {
-		fmt.Printf("error selecting file: %v\n", err)
-		os.Exit(1)
+		return fmt.Errorf("failed to stat source file: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return fmt.Errorf("failed to create destination directory: %w", err)
+	}
+	if err := os.WriteFile(dst, content, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to write destination file: %w", err)
 	}
+	return nil
+}
 
-	if selectedFile == "" {
-		fmt.Println("no file selected")
-		os.Exit(1)
+// opens $EDITOR on a temp file holding initial and returns its path once
+// the editor exits ✏️
+func composeBodyInEditor(initial string) (string, error) {
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = "vi"
+	}
+	// $EDITOR may carry arguments, e.g. "code --wait"
+	editorArgs, err := splitArgs(editor)
+	if err != nil || len(editorArgs) == 0 {
+		return "", fmt.Errorf("invalid $EDITOR '%s'", editor)
 	}
 
-	// generate branch name if not provided 🌿
-	finalBranchName := *branchName
-	if finalBranchName == "" {
-		finalBranchName = generateBranchName(selectedFile)
+	bodyFile, err := writeBody(initial)
+	if err != nil {
+		return "", err
+	}
+
+	logCommand("", nil, editorArgs[0], append(editorArgs[1:], bodyFile)...)
+	// interactive, so only -total-timeout applies
+	cmd := exec.CommandContext(runCtx, editorArgs[0], append(editorArgs[1:], bodyFile)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		os.Remove(bodyFile)
+		if runCtx.Err() != nil {
+			return "", timeoutError(runCtx, "the editor", err)
+		}
+		return "", fmt.Errorf("editor '%s' failed: %w", editor, err)
 	}
 
-	// source and destination paths 📂
-	sourcePath := filepath.Join(absSearchDir, selectedFile)
-	// use only the base filename for the destination
-	destPath := filepath.Join(absTargetDir, filepath.Base(selectedFile))
+	return bodyFile, nil
+}
+
+// returns the default emoji pr body 🐦
+func defaultBody() string {
+	// get two random emojis for the new pr
+	happy, bird := getRandomEmojis()
+	return fmt.Sprintf("New finding! %s%s", happy, bird)
+}
+
+// writes a pr body to a temp file and returns its path
+func writeBody(body string) (string, error) {
+	tmpFile, err := os.CreateTemp("", "elf-owl-body-*.md")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer tmpFile.Close()
 
-	// copy the file 📋
-	fmt.Printf("copying %s to %s...\n", sourcePath, destPath)
-	if err := copyFile(sourcePath, destPath); err != nil {
-		fmt.Printf("error copying file: %v\n", err)
-		os.Exit(1)
+	if _, err := tmpFile.WriteString(body); err != nil {
+		os.Remove(tmpFile.Name())
+		return "", fmt.Errorf("failed to write pr body: %w", err)
 	}
 
-	// perform git operations 🔄
-	fmt.Printf("performing git operations...\n")
-	if err := gitOperations(finalBranchName, absTargetDir); err != nil {
-		fmt.Printf("error in git operations: %v\n", err)
-		os.Exit(1)
+	return tmpFile.Name(), nil
+}
+
+// where github looks for a pull request template, relative to the repo root
+var prTemplatePaths = []string{
+	".github/pull_request_template.md",
+	".github/PULL_REQUEST_TEMPLATE.md",
+	"pull_request_template.md",
+	"PULL_REQUEST_TEMPLATE.md",
+	"docs/pull_request_template.md",
+	"docs/PULL_REQUEST_TEMPLATE.md",
+}
+
+// returns the contents of the target repo's pr template, or "" if it has none 📄
+func findPRTemplate(targetDir string) (string, error) {
+	root, err := commandOutput(targetDir, "git", "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", fmt.Errorf("failed to find repo root: %w", err)
 	}
 
-	fmt.Println("successfully completed all operations! 🎉")
+	for _, rel := range prTemplatePaths {
+		content, err := os.ReadFile(filepath.Join(root, rel))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to read pr template: %w", err)
+		}
+		logVerbose("using pr template %s", rel)
+		return string(content), nil
+	}
+	return "", nil
+}
+
+// returned by gitOperations when the copies match what's already committed
+var errNoChanges = errors.New("no changes to commit")
+
+// settings for a single gitOperations run 🔄
+type gitOptions struct {
+	branchName         string
+	targetDir          string
+	bodyFile           string
+	files              []string // absolute destination paths of the copied files
+	commitPerFile      bool
+	autoMerge          bool
+	mergeMethod        string   // squash, merge or rebase
+	mergeSubject       string   // title of the merge commit, empty for github's
+	mergeBody          string   // body of the merge commit, empty for github's
+	assignees          []string // github logins, @me for the author
+	sign               bool
+	signoff            bool
+	author             string   // "Name <email>", empty for the git config identity
+	commitBody         string   // extra commit message paragraphs after the subject
+	commitType         string   // conventional commits type, e.g. feat, empty for none
+	commitScope        string   // conventional commits scope, needs commitType
+	from               string   // start point for the new branch, empty for HEAD
+	fetch              bool     // fetch origin before creating the branch
+	base               string   // branch the pr targets, empty for the repo default
+	update             bool     // rebase or merge onto the latest base before pushing
+	updateStrategy     string   // rebase or merge
+	branchCmd          []string // command that names branches, empty for generateBranchName
+	onExists           string   // fail, suffix, force or switch when the branch exists
+	skipBrowse         bool
+	noReuse            bool        // fail rather than reuse an open pr for the branch
+	noPR               bool        // stop after committing locally
+	pushOnly           bool        // push, then print the compare url instead of opening a pr
+	noCommit           bool        // only stage the copied files on the current branch
+	prePRHook          []string    // validation command run after push, before the pr
+	noRollback         bool        // keep the pushed branch when the pre-pr hook fails
+	deleteRemoteOnFail bool        // delete the pushed branch when pr creation fails
+	remote             string      // git remote to fetch from and push to
+	ghRepo             string      // owner/name the pr is opened against, empty for gh's guess
+	previewDiff        bool        // show the committed diff and ask before going on
+	yes                bool        // don't ask, e.g. after -preview-diff
+	summary            *runSummary // filled in with the branch, commit and pr, nil to skip
+	labelSegment       int         // directory of each file's path to label its pr with, 0 for none
+	labels             []string    // labels for the pr
+}
+
+// the types the conventional commits spec and commitlint know 📐
+var conventionalTypes = []string{"build", "chore", "ci", "docs", "feat", "fix", "perf", "refactor", "revert", "style", "test"}
+
+// checks -commit-type and -commit-scope, warning about a type outside conventionalTypes
+func validateCommitType(commitType, scope string) error {
+	if scope != "" && commitType == "" {
+		return fmt.Errorf("-commit-scope requires -commit-type")
+	}
+	if commitType == "" {
+		return nil
+	}
+	if strings.ContainsAny(commitType, " ():!\n") {
+		return fmt.Errorf("invalid -commit-type '%s'", commitType)
+	}
+	if strings.ContainsAny(scope, "()\n") {
+		return fmt.Errorf("invalid -commit-scope '%s'", scope)
+	}
+	if !slices.Contains(conventionalTypes, commitType) {
+		logWarning("-commit-type '%s' isn't one of %s, a commit linter may reject it", commitType, strings.Join(conventionalTypes, ", "))
+	}
+	return nil
+}
+
+// prefixes subject with type(scope): when a commit type is set,
+// lowercasing its first letter as commitlint's subject-case wants
+func commitSubject(opts gitOptions, subject string) string {
+	if opts.commitType == "" {
+		return subject
+	}
+	prefix := opts.commitType
+	if opts.commitScope != "" {
+		prefix += "(" + opts.commitScope + ")"
+	}
+	if r, size := utf8.DecodeRuneInString(subject); size > 0 {
+		subject = string(unicode.ToLower(r)) + subject[size:]
+	}
+	return prefix + ": " + subject
+}
+
+// commits staged changes with the given message, honoring signing options 📝
+func gitCommit(opts gitOptions, message string) error {
+	args := []string{"commit", "-m", commitSubject(opts, message)}
+	if opts.commitBody != "" {
+		// git puts the blank line between the subject and body -m paragraphs
+		args = append(args, "-m", opts.commitBody)
+	}
+	if opts.sign {
+		args = append(args, "-S")
+	}
+	if opts.signoff {
+		args = append(args, "--signoff")
+	}
+	if opts.author != "" {
+		args = append(args, "--author="+opts.author)
+	}
+	if err := runCommand(opts.targetDir, "git", args...); err != nil {
+		if opts.sign {
+			return fmt.Errorf("%w (is a signing key configured for git?)", err)
+		}
+		return err
+	}
+	return nil
+}
+
+// does a minimal sanity check of an email address ✉️
+func validEmail(email string) bool {
+	local, domain, ok := strings.Cut(email, "@")
+	return ok && local != "" && domain != "" &&
+		!strings.ContainsAny(email, " <>") && !strings.Contains(domain, "@")
+}
+
+// returns the url of the open pr for head (a branch, or owner:branch for
+// a fork's), or "" if there is none 🔎
+func findOpenPR(dir, head, ghRepo string) string {
+	args := []string{"pr", "view", head, "--json", "url,state", "--jq", `select(.state == "OPEN") | .url`}
+	url, err := commandOutput(dir, "gh", append(args, repoArgs(ghRepo)...)...)
+	if err != nil {
+		return ""
+	}
+	return url
+}
+
+// prints what the branch adds since start and asks whether to go on,
+// unless yes is set
+func previewDiff(dir, start string, yes bool) error {
+	args := []string{"diff", "--stat", "--patch"}
+	if colorEnabled {
+		args = append(args, "--color=always")
+	}
+	diff, err := commandOutput(dir, "git", append(args, start, "HEAD")...)
+	if err != nil {
+		return fmt.Errorf("failed to show the diff: %w", err)
+	}
+	fmt.Println(diff)
+
+	if yes {
+		return nil
+	}
+	if !isInteractive() {
+		return fmt.Errorf("can't ask to continue after -preview-diff without a terminal, pass -yes")
+	}
+	ok, err := confirm("continue?")
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("%w after the preview", errCancelled)
+	}
+	return nil
+}
+
+// copies the urls to the clipboard, one per line, warning if it can't
+func copyURLs(urls []string) {
+	if err := copyToClipboard(strings.Join(urls, "\n")); err != nil {
+		logWarning("couldn't copy the pr url: %v", err)
+		return
+	}
+	logInfo("copied the pr url to the clipboard")
+}
+
+// checks that gh is logged in to the remote's host before anything is
+// pushed, so an expired token can't leave a branch without its pr 🔑
+func checkGhAuth(dir, remote string) error {
+	args := []string{"auth", "status"}
+	// a github enterprise remote needs its own login
+	if r, err := remoteRepoOf(dir, remote); err == nil {
+		args = append(args, "--hostname", r.host)
+	}
+	// from the cwd, since an -init-repo target may not exist yet
+	if _, err := commandOutput("", "gh", args...); err != nil {
+		if errors.Is(err, errCommandTimeout) || errors.Is(err, errTotalTimeout) {
+			return err
+		}
+		logVerbose("gh auth status: %v", err)
+		return fmt.Errorf("GitHub CLI is not authenticated — run gh auth login")
+	}
+	return nil
+}
+
+// returns the --repo arguments for gh, none when it should infer the repo
+func repoArgs(ghRepo string) []string {
+	if ghRepo == "" {
+		return nil
+	}
+	return []string{"--repo", ghRepo}
+}
+
+// returns what gh should use as the pr's head: the branch itself, or
+// owner:branch when the pr goes to another repo than the one pushed to
+func prHead(dir, remote, ghRepo, branch string) string {
+	if ghRepo == "" {
+		return branch
+	}
+	r, err := remoteRepoOf(dir, remote)
+	if err != nil || strings.EqualFold(r.owner+"/"+r.repo, ghRepo) {
+		return branch
+	}
+	return r.owner + ":" + branch
+}
+
+// reports whether branch exists in the local repo
+func localBranchExists(dir, branch string) bool {
+	_, err := commandOutput(dir, "git", "rev-parse", "--verify", "--quiet", "refs/heads/"+branch)
+	return err == nil
+}
+
+// returns name as git would have it as a branch, slashes and all, for
+// names the user or a -branch-cmd picked rather than one we derived
+func checkBranchName(dir, name string) (string, error) {
+	valid, err := commandOutput(dir, "git", "check-ref-format", "--branch", name)
+	if err != nil {
+		return "", fmt.Errorf("%q, which isn't a valid branch name", name)
+	}
+	return valid, nil
+}
+
+// runs git checkout with args, carrying the copied files over as they
+// are, since git won't switch over changes to files the branch or start
+// point has its own version of 🔀
+func checkoutKeepingCopies(dir string, files []string, args ...string) error {
+	type saved struct {
+		path    string
+		link    string // target, when the copy is a symlink
+		content []byte
+		mode    os.FileMode
+	}
+	var copies []saved
+	for _, file := range files {
+		info, err := os.Lstat(file)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		c := saved{path: file, mode: info.Mode()}
+		if info.Mode()&os.ModeSymlink != 0 {
+			c.link, err = os.Readlink(file)
+		} else {
+			c.content, err = os.ReadFile(file)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		copies = append(copies, c)
+	}
+
+	if err := resetCopies(dir, files); err != nil {
+		return err
+	}
+
+	if err := runCommand(dir, "git", append([]string{"checkout"}, args...)...); err != nil {
+		return err
+	}
+
+	for _, c := range copies {
+		if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+			return fmt.Errorf("failed to restore %s: %w", c.path, err)
+		}
+		os.Remove(c.path)
+		var err error
+		if c.mode&os.ModeSymlink != 0 {
+			err = os.Symlink(c.link, c.path)
+		} else {
+			err = os.WriteFile(c.path, c.content, c.mode.Perm())
+		}
+		if err != nil {
+			return fmt.Errorf("failed to restore %s: %w", c.path, err)
+		}
+	}
+	return nil
+}
+
+// puts the copied files back the way HEAD has them: unstaged, and removed
+// when HEAD doesn't track them
+func resetCopies(dir string, files []string) error {
+	for _, file := range files {
+		rel, err := filepath.Rel(dir, file)
+		if err != nil {
+			return fmt.Errorf("failed to resolve %s: %w", file, err)
+		}
+		if err := runCommand(dir, "git", "reset", "-q", "--", rel); err != nil {
+			return fmt.Errorf("failed to unstage %s: %w", rel, err)
+		}
+		if _, err := commandOutput(dir, "git", "cat-file", "-e", "HEAD:"+filepath.ToSlash(rel)); err == nil {
+			err = runCommand(dir, "git", "checkout", "--", rel)
+		} else {
+			err = os.Remove(file)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to set %s aside: %w", rel, err)
+		}
+	}
+	return nil
+}
+
+// reports whether the copied files match what ref has, staging them in a
+// throwaway index so the user's own is left alone 🟰
+func copiesUnchanged(dir, ref string, relFiles []string) (bool, error) {
+	tmp, err := os.MkdirTemp("", "elf-owl-index-*")
+	if err != nil {
+		return false, fmt.Errorf("failed to create a temporary index: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+	env := []string{"GIT_INDEX_FILE=" + filepath.Join(tmp, "index")}
+
+	if _, err := execCommand(dir, env, false, "git", "read-tree", ref); err != nil {
+		return false, fmt.Errorf("failed to read %s: %w", ref, err)
+	}
+	if _, err := execCommand(dir, env, false, "git", append([]string{"add"}, relFiles...)...); err != nil {
+		return false, fmt.Errorf("failed to stage changes: %w", err)
+	}
+	_, err = execCommand(dir, env, false, "git", append([]string{"diff", "--cached", "--quiet", ref}, relFiles...)...)
+	return err == nil, nil
+}
+
+// returns branch-2, branch-3, ... whichever is first free both locally
+// and on the remote
+func freeBranchName(dir, remote, branch string) (string, error) {
+	for n := 2; ; n++ {
+		candidate := fmt.Sprintf("%s-%d", branch, n)
+		if localBranchExists(dir, candidate) {
+			continue
+		}
+		onRemote, err := remoteBranch(dir, remote, candidate)
+		if err != nil {
+			return "", err
+		}
+		if !onRemote {
+			return candidate, nil
+		}
+	}
+}
+
+// reports whether the remote has branch, failing when it can't be asked
+func remoteBranch(dir, remote, branch string) (bool, error) {
+	out, err := commandOutput(dir, "git", "ls-remote", "--heads", remote, "refs/heads/"+branch)
+	if err != nil {
+		return false, fmt.Errorf("failed to look up %s on %s: %w", branch, remote, err)
+	}
+	return out != "", nil
+}
+
+// brings the remote's branch into its remote-tracking ref, e.g. origin/branch
+func fetchRemoteBranch(dir, remote, branch string) error {
+	refspec := fmt.Sprintf("+refs/heads/%s:refs/remotes/%s/%s", branch, remote, branch)
+	if err := runCommand(dir, "git", "fetch", remote, refspec); err != nil {
+		return fmt.Errorf("failed to fetch %s from %s: %w", branch, remote, err)
+	}
+	return nil
+}
+
+// deletes a branch this run pushed, logging rather than returning failures
+// since the caller is already handling another error
+func deleteRemoteBranch(dir, remote, branch string) {
+	logInfo("deleting pushed branch %s...", branch)
+	if err := runCommand(dir, "git", "push", remote, "--delete", branch); err != nil {
+		logError("error deleting remote branch %s: %v", branch, err)
+	}
+}
+
+// returns the remote's default branch, e.g. main, from its remote HEAD
+func defaultBranch(dir, remote string) (string, error) {
+	ref, err := commandOutput(dir, "git", "symbolic-ref", "--short", "refs/remotes/"+remote+"/HEAD")
+	if err != nil {
+		return "", fmt.Errorf("can't tell %s's default branch, pass -base (or run git remote set-head %s --auto): %w", remote, remote, err)
+	}
+	return strings.TrimPrefix(ref, remote+"/"), nil
+}
+
+// fetches base and rebases (or merges) the current branch onto it,
+// aborting and leaving the branch as committed if there are conflicts
+func updateBranch(dir, remote, base, strategy string) error {
+	upstream := remote + "/" + base
+	logInfo("updating against %s...", upstream)
+	if err := runCommand(dir, "git", "fetch", remote, base); err != nil {
+		return fmt.Errorf("failed to fetch %s: %w", upstream, err)
+	}
+
+	args := []string{"rebase", upstream}
+	if strategy == "merge" {
+		args = []string{"merge", "--no-edit", upstream}
+	}
+	if err := runCommand(dir, "git", args...); err != nil {
+		if abortErr := runCommand(dir, "git", args[0], "--abort"); abortErr != nil {
+			logError("error aborting %s: %v", args[0], abortErr)
+		}
+		return fmt.Errorf("%s onto %s failed and was aborted, the branch is left as committed, resolve it by hand with: git %s: %v",
+			args[0], upstream, formatCommand(args[0], args[1:]...), err)
+	}
+	return nil
+}
+
+// handles all git and github cli operations in the target directory 🔄
+// returns the url of the created pr
+func gitOperations(opts gitOptions) (string, error) {
+	branchName := opts.branchName
+	dir := opts.targetDir
+	logVerbose("working in %s", dir)
+
+	// stage just the copied files for the user's own commit 📥
+	if opts.noCommit {
+		args := []string{"add", "--"}
+		for _, file := range opts.files {
+			relPath, err := filepath.Rel(dir, file)
+			if err != nil {
+				return "", fmt.Errorf("failed to resolve %s: %w", file, err)
+			}
+			args = append(args, relPath)
+		}
+		if err := runCommand(dir, "git", args...); err != nil {
+			return "", fmt.Errorf("failed to stage changes: %w", err)
+		}
+		logInfo("staged %d file(s) on the current branch, review with: git diff --cached", len(opts.files))
+		return "", nil
+	}
+
+	// work out the base now rather than fail after committing
+	if opts.update && opts.base == "" {
+		var err error
+		if opts.base, err = defaultBranch(dir, opts.remote); err != nil {
+			return "", err
+		}
+	}
+
+	// bring remote branches like origin/main up to date first 📥
+	if opts.fetch {
+		if err := runCommand(dir, "git", "fetch", opts.remote); err != nil {
+			return "", fmt.Errorf("failed to fetch %s: %w", opts.remote, err)
+		}
+	}
+
+	// create and checkout new branch, minding -on-exists 🌿
+	// a branch only on the remote counts too, it's where the push would go
+	createFlag := "-b"
+	forcePush := false
+	local := localBranchExists(dir, branchName)
+	// one lookup serves both -on-exists and the cleanup after a failed push
+	var onRemote bool
+	var remoteErr error
+	if !opts.noPR {
+		if onRemote, remoteErr = remoteBranch(dir, opts.remote, branchName); remoteErr != nil {
+			logVerbose("%v", remoteErr)
+		}
+	}
+	remoteOnly := !local && onRemote
+	if remoteOnly && (opts.onExists == "force" || opts.onExists == "switch") {
+		// switch builds on it and force leases against it
+		if err := fetchRemoteBranch(dir, opts.remote, branchName); err != nil {
+			return "", err
+		}
+	}
+	if local || remoteOnly {
+		switch opts.onExists {
+		case "fail":
+			where := "locally"
+			if remoteOnly {
+				where = "on " + opts.remote
+			}
+			return "", fmt.Errorf("branch %s already exists %s, pick another -branch or -on-exists", branchName, where)
+		case "suffix":
+			var err error
+			if branchName, err = freeBranchName(dir, opts.remote, branchName); err != nil {
+				return "", err
+			}
+			// picked for being free on the remote
+			onRemote, remoteErr = false, nil
+			logInfo("branch %s already exists, using %s", opts.branchName, branchName)
+		case "force":
+			logInfo("resetting existing branch %s", branchName)
+			createFlag = "-B"
+			forcePush = true
+		case "switch":
+			logInfo("committing onto existing branch %s", branchName)
+			createFlag = ""
+		}
+	}
+
+	// what the branch starts from: itself when switching onto it
+	startRef := "HEAD"
+	if createFlag == "" && remoteOnly {
+		startRef = opts.remote + "/" + branchName
+	} else if createFlag == "" {
+		startRef = branchName
+	} else if opts.from != "" {
+		startRef = opts.from
+	}
+
+	// copies identical to what the branch starts from leave nothing to
+	// commit or pr 🟰
+	relFiles := []string{"--"}
+	for _, file := range opts.files {
+		relPath, err := filepath.Rel(dir, file)
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve %s: %w", file, err)
+		}
+		relFiles = append(relFiles, relPath)
+	}
+	unchanged, err := copiesUnchanged(dir, startRef, relFiles)
+	if err != nil {
+		return "", err
+	}
+	if unchanged {
+		// nothing is left of the run on the current branch either
+		if err := resetCopies(dir, opts.files); err != nil {
+			return "", err
+		}
+		return "", errNoChanges
+	}
+
+	if createFlag == "" && remoteOnly {
+		if err := checkoutKeepingCopies(dir, opts.files, "-b", branchName, "--track", startRef); err != nil {
+			return "", fmt.Errorf("failed to switch to branch %s: %w", branchName, err)
+		}
+	} else if createFlag == "" {
+		if err := checkoutKeepingCopies(dir, opts.files, branchName); err != nil {
+			return "", fmt.Errorf("failed to switch to branch %s: %w", branchName, err)
+		}
+	} else if opts.from != "" {
+		if err := checkoutKeepingCopies(dir, opts.files, createFlag, branchName, opts.from); err != nil {
+			return "", fmt.Errorf("failed to create branch: %w", err)
+		}
+	} else if err := runCommand(dir, "git", "checkout", createFlag, branchName); err != nil {
+		return "", fmt.Errorf("failed to create branch: %w", err)
+	}
+	// where the branch started, for -preview-diff
+	startCommit, err := commandOutput(dir, "git", "rev-parse", "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("failed to read HEAD: %w", err)
+	}
+
+	if opts.commitPerFile {
+		// stage and commit each copied file on its own 📝
+		for _, file := range opts.files {
+			relPath, err := filepath.Rel(opts.targetDir, file)
+			if err != nil {
+				return "", fmt.Errorf("failed to resolve %s: %w", file, err)
+			}
+			if err := runCommand(dir, "git", "add", "--", relPath); err != nil {
+				return "", fmt.Errorf("failed to stage %s: %w", relPath, err)
+			}
+			if _, err := commandOutput(dir, "git", "diff", "--cached", "--quiet", "--", relPath); err == nil {
+				logInfo("%s is unchanged, not committing it", relPath)
+				continue
+			}
+			if err := gitCommit(opts, fmt.Sprintf("Add %s", relPath)); err != nil {
+				return "", fmt.Errorf("failed to commit %s: %w", relPath, err)
+			}
+		}
+	} else {
+		// stage changes
+		if err := runCommand(dir, "git", append([]string{"add"}, relFiles...)...); err != nil {
+			return "", fmt.Errorf("failed to stage changes: %w", err)
+		}
+
+		// commit changes 📝
+		if err := gitCommit(opts, fmt.Sprintf("Add %s", branchName)); err != nil {
+			return "", fmt.Errorf("failed to commit changes: %w", err)
+		}
+	}
+
+	// the commit the summary reports, read once the branch is final since
+	// -update can still rebase or merge on top of it
+	recordCommit := func() error {
+		if opts.summary == nil {
+			return nil
+		}
+		opts.summary.branch = branchName
+		var err error
+		if opts.summary.commit, err = commandOutput(dir, "git", "rev-parse", "HEAD"); err != nil {
+			return fmt.Errorf("failed to read HEAD: %w", err)
+		}
+		return nil
+	}
+
+	// one last look before anything leaves the machine 👀
+	if opts.previewDiff {
+		if err := previewDiff(dir, startCommit, opts.yes); err != nil {
+			return "", fmt.Errorf("%v, the commit stays on branch %s", err, branchName)
+		}
+	}
+
+	// leave pushing and the pr to the user 🛑
+	if opts.noPR {
+		if err := recordCommit(); err != nil {
+			return "", err
+		}
+		logInfo("committed on branch %s, push it later with: git push --set-upstream %s %s", branchName, opts.remote, branchName)
+		return "", nil
+	}
+
+	// only a branch this run pushes first may be deleted again, so one
+	// whose lookup failed counts as one that might predate the run 🧹
+	createdRemote := !onRemote && remoteErr == nil
+
+	// catch up with the base so the pr isn't behind 🔃
+	if opts.update {
+		if onRemote {
+			// lease against where the pushed branch is now
+			if err := fetchRemoteBranch(dir, opts.remote, branchName); err != nil {
+				return "", err
+			}
+		}
+		if err := updateBranch(dir, opts.remote, opts.base, opts.updateStrategy); err != nil {
+			return "", err
+		}
+		if onRemote {
+			pushed := opts.remote + "/" + branchName
+			if _, err := commandOutput(dir, "git", "merge-base", "--is-ancestor", pushed, "HEAD"); err != nil {
+				logInfo("the %s rewrote the pushed %s, force-pushing it (with lease)", opts.updateStrategy, branchName)
+				forcePush = true
+			}
+		}
+	}
+	if err := recordCommit(); err != nil {
+		return "", err
+	}
+
+	// push changes ⬆️
+	pushArgs := []string{"push", "--set-upstream", opts.remote, branchName}
+	if forcePush {
+		// a reset or rebased branch no longer fast-forwards from what was pushed
+		pushArgs = append(pushArgs, "--force-with-lease")
+	}
+	if err := runCommand(dir, "git", pushArgs...); err != nil {
+		return "", fmt.Errorf("failed to push changes: %w", err)
+	}
+
+	// validate the pushed change before opening the pr 🪝
+	if len(opts.prePRHook) > 0 {
+		if err := runPrePRHook(opts.prePRHook, dir, branchName, opts.files); err != nil {
+			if !opts.noRollback && createdRemote {
+				deleteRemoteBranch(dir, opts.remote, branchName)
+			}
+			return "", err
+		}
+	}
+
+	// leave opening the pr to someone else 🔗
+	if opts.pushOnly {
+		url, err := compareURL(dir, opts.remote, branchName)
+		if err != nil {
+			return "", fmt.Errorf("pushed %s but %w", branchName, err)
+		}
+		logInfo("pushed %s, open a pr at: %s", branchName, url)
+		return url, nil
+	}
+
+	// reuse an open pr for this branch instead of failing to create one ♻️
+	head := prHead(dir, opts.remote, opts.ghRepo, branchName)
+	prURL := ""
+	if !opts.noReuse {
+		prURL = findOpenPR(dir, head, opts.ghRepo)
+		if prURL != "" {
+			logInfo("pushed to existing pr: %s", prURL)
+		}
+	}
+
+	// create pr 🎯
+	if prURL == "" {
+		prArgs := []string{"pr", "create",
+			"--title", branchName,
+			"--body-file", opts.bodyFile}
+		if opts.base != "" {
+			prArgs = append(prArgs, "--base", opts.base)
+		}
+		for _, assignee := range opts.assignees {
+			prArgs = append(prArgs, "--assignee", assignee)
+		}
+		for _, label := range opts.labels {
+			prArgs = append(prArgs, "--label", label)
+		}
+		if opts.ghRepo != "" {
+			prArgs = append(prArgs, "--head", head)
+			prArgs = append(prArgs, repoArgs(opts.ghRepo)...)
+		}
+		var err error
+		prURL, err = runCommandOutput(dir, "gh", prArgs...)
+		if err != nil {
+			if opts.deleteRemoteOnFail && createdRemote {
+				deleteRemoteBranch(dir, opts.remote, branchName)
+			}
+			return "", fmt.Errorf("failed to create pr: %w", err)
+		}
+	}
+
+	// enable auto-merge once checks pass 🤖
+	if opts.autoMerge {
+		mergeArgs := []string{"pr", "merge", "--auto", "--" + opts.mergeMethod}
+		if opts.ghRepo != "" {
+			// gh can't find the pr from the checkout when it's in another repo
+			mergeArgs = append([]string{"pr", "merge", head}, mergeArgs[2:]...)
+			mergeArgs = append(mergeArgs, repoArgs(opts.ghRepo)...)
+		}
+		if opts.mergeSubject != "" {
+			mergeArgs = append(mergeArgs, "--subject", opts.mergeSubject)
+		}
+		if opts.mergeBody != "" {
+			mergeArgs = append(mergeArgs, "--body", opts.mergeBody)
+		}
+		if err := runCommand(dir, "gh", mergeArgs...); err != nil {
+			return "", fmt.Errorf("failed to enable auto-merge (is it allowed on this repo?): %w", err)
+		}
+	}
+
+	// open in browser 🌐
+	if !opts.skipBrowse {
+		if err := runCommand(dir, "gh", append([]string{"browse"}, repoArgs(opts.ghRepo)...)...); err != nil {
+			return "", fmt.Errorf("failed to open browser: %w", err)
+		}
+	}
+
+	return prURL, nil
+}
+
+// prints usage for every flag that isn't hidden 📖
+func usage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintf(out, "Usage of %s:\n", os.Args[0])
+
+	visible := flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+	visible.SetOutput(out)
+	for _, f := range visibleFlags() {
+		visible.Var(f.Value, f.Name, f.Usage)
+		visible.Lookup(f.Name).DefValue = f.DefValue
+	}
+	visible.PrintDefaults()
+
+	fmt.Fprintf(out, "\nDefaults can be set as 'flag: value' lines in %s\n", strings.Join(configPaths(), " or "))
+	fmt.Fprintf(out, "Every flag can also be set via %s<flag> (e.g. %s)\n", envPrefix, envVarName("body-file"))
+	fmt.Fprintln(out, "Precedence: command-line flags > environment > config file > built-in defaults")
+	fmt.Fprintf(out, "Exit codes: 0 ok, %d error, %d missing command, %d git or gh failed, %d timed out, %d cancelled\n",
+		exitFailure, exitMissingCommand, exitGitFailure, exitTimeout, exitCancelled)
+}
+
+func main() {
+	if err := run(); err != nil {
+		logError("%v", err)
+		os.Exit(exitCodeFor(err))
+	}
+}
+
+// does the whole run, returning an error whose exit code main reports 🦉
+func run() error {
+	// define flags 🚩
+	var searchDirs stringList
+	flag.Var(&searchDirs, "search", "directory to search for files, repeat or separate with commas for several (required)")
+	targetDir := flag.String("target", ".", "target directory (optional)")
+	branchName := flag.String("branch", "", "branch name (optional) (default <selected file name>)")
+	bodyFile := flag.String("body-file", "", "read the pr body from a file (optional)")
+	bodyEdit := flag.Bool("body-edit", false, "compose the pr body in $EDITOR (optional)")
+	commitBody := flag.String("commit-body", "", "commit message body added below the subject line (optional)")
+	commitType := flag.String("commit-type", "", "conventional commits type to prefix the subject with, e.g. feat or docs (optional)")
+	commitScope := flag.String("commit-scope", "", "with -commit-type, scope to add as type(scope): (optional)")
+	commitBodyFile := flag.String("commit-body-file", "", "read the commit message body from a file (optional)")
+	usePRTemplate := flag.Bool("use-pr-template", false, "use the target repo's pull request template as the pr body (optional)")
+	multi := flag.Bool("multi", false, "select several files in fzf and copy them all into one pr (optional)")
+	commitPerFile := flag.Bool("commit-per-file", false, "with -multi, commit each copied file separately (optional)")
+	followSymlinks := flag.Bool("follow-symlinks", false, "descend into symlinked directories and copy link targets instead of links (optional)")
+	destSubdir := flag.String("dest-subdir", "", "subdirectory of the target to copy into (optional)")
+	keepParents := flag.Int("keep-parents", 0, "recreate this many of the file's parent directories in the target, e.g. 1 copies a/security/x.md to security/x.md (optional)")
+	postCopyHook := flag.String("post-copy-hook", "", "command to run on each copied file before committing, {file} is replaced by its path (optional)")
+	prePRHook := flag.String("pre-pr-hook", "", "command to run after pushing, a non-zero exit stops the pr (optional)")
+	noRollback := flag.Bool("no-rollback", false, "keep the pushed branch when -pre-pr-hook fails (optional)")
+	deleteRemoteOnFail := flag.Bool("delete-remote-on-fail", false, "delete the pushed branch if creating the pr fails, when this run created it (optional)")
+	noReuse := flag.Bool("no-reuse", false, "fail instead of reusing an open pr for the branch (optional)")
+	rename := flag.String("rename", "", "file name to give the copied file in the target (optional)")
+	concurrent := flag.Bool("concurrent", false, "walk the search directory in parallel, for large or network-mounted trees (optional)")
+	since := flag.Duration("since", 0, "only list files modified within this duration, e.g. 24h (optional)")
+	var minSize, maxSize byteSize
+	flag.Var(&minSize, "min-size", "only list files of at least this `size`, e.g. 500KB (optional)")
+	flag.Var(&maxSize, "max-size", "only list files of at most this `size`, e.g. 10MB (optional)")
+	maxFiles := flag.Int("max-files", defaultMaxFiles, "stop looking once more than this many files match, 0 for no limit")
+	pattern := flag.String("pattern", "", "only list files whose name or relative path matches this glob, e.g. '*.md' (optional)")
+	selectAll := flag.Bool("all", false, "select every listed file without fzf and copy them into one pr (optional)")
+	manifestPath := flag.String("manifest", "", "write a json (or .csv) record of the copied files to this path (optional)")
+	manifestAppend := flag.Bool("manifest-append", false, "add to an existing -manifest instead of replacing it (optional)")
+	expand := flag.Bool("expand", false, "fill in {{DATE}}, {{BRANCH}}, {{USER}}, {{AUTHOR}} and -var placeholders in copied text files (optional)")
+	var expandVars varFlags
+	flag.Var(&expandVars, "var", "set a `key=value` placeholder for -expand, repeatable (optional)")
+	checkSpaceFlag := flag.Bool("check-space", false, "refuse to copy over 10MB that won't fit on the target's disk (optional)")
+	preserveXattrs := flag.Bool("preserve-xattrs", false, "copy extended attributes along with each file, on linux and macos (optional)")
+	textOnly := flag.Bool("text-only", false, "refuse to copy files that look binary (optional)")
+	separatePRs := flag.Bool("separate-prs", false, "select several files and open a separate branch and pr for each (optional)")
+	watch := flag.Bool("watch", false, "keep running and open a branch and pr for each new file in the search directories, requires -yes (optional)")
+	autoMerge := flag.Bool("auto-merge", false, "enable auto-merge on the created pr (optional)")
+	mergeMethod := flag.String("merge-method", "squash", "merge method for -auto-merge: squash, merge or rebase")
+	mergeSubject := flag.String("merge-subject", "", "with -auto-merge, subject of the eventual squash or merge commit (optional)")
+	mergeBody := flag.String("merge-body", "", "with -auto-merge, body of the eventual squash or merge commit (optional)")
+	assignSelf := flag.Bool("assign-self", false, "assign the pr to yourself (optional)")
+	assignee := flag.String("assignee", "", "comma-separated github logins to assign the pr to (optional)")
+	sign := flag.Bool("sign", false, "sign commits with your configured gpg/ssh key (optional)")
+	signoff := flag.Bool("signoff", false, "add a Signed-off-by trailer to commits (optional)")
+	authorName := flag.String("author-name", "", "commit author name, requires -author-email (optional)")
+	authorEmail := flag.String("author-email", "", "commit author email, requires -author-name (optional)")
+	branchCmd := flag.String("branch-cmd", "", "command printing the branch name for a file, {file} and {date} are replaced (optional)")
+	onExists := flag.String("on-exists", "", "when the branch already exists: fail, suffix (-2, -3...), force (reset it) or switch (commit onto it) (default switch, or fail with -no-reuse)")
+	previewDiffFlag := flag.Bool("preview-diff", false, "show the committed diff and ask before pushing (optional)")
+	clipboard := flag.Bool("clipboard", false, "copy the pr url to the clipboard (optional)")
+	labelFromPath := flag.Bool("label-from-path", false, "label the pr with a directory of the selected file's path, see -label-segment (optional)")
+	labelSegment := flag.Int("label-segment", 1, "with -label-from-path, which directory of the path to use, counting from 1")
+	editBranch := flag.Bool("edit-branch", false, "edit the branch name at a prompt before committing (optional)")
+	yes := flag.Bool("yes", false, "skip interactive prompts, accepting the defaults (optional)")
+	showVersion := flag.Bool("version", false, "print version info and exit")
+	completion := flag.String("completion", "", "print a completion script for bash, zsh or fish and exit")
+	noPR := flag.Bool("no-pr", false, "only copy and commit, skipping push and pr creation (optional)")
+	initRepoFlag := flag.Bool("init-repo", false, "run git init in the target if it isn't a repo yet (optional)")
+	remoteURL := flag.String("remote-url", "", "with -init-repo, url of the -remote to add to the new repo (optional)")
+	from := flag.String("from", "", "start the new branch from this commit or branch, e.g. origin/main (optional)")
+	fetch := flag.Bool("fetch", false, "with -from, fetch the remote first so it is up to date (optional)")
+	remote := flag.String("remote", "origin", "git remote to push the branch to")
+	ghRepo := flag.String("gh-repo", "", "open the pr against this owner/name repo, e.g. an upstream of your fork (optional)")
+	base := flag.String("base", "", "branch the pr targets and -update syncs with (optional) (default the repo's default branch)")
+	update := flag.Bool("update", false, "fetch the base and rebase onto it before pushing (optional)")
+	updateStrategy := flag.String("update-strategy", "rebase", "how -update catches up with the base: rebase or merge")
+	pushOnly := flag.Bool("push-only", false, "commit and push, then print the compare url instead of opening a pr (optional)")
+	noCommit := flag.Bool("no-commit", false, "only copy and stage the files on the current branch (optional)")
+	useWorktree := flag.Bool("worktree", false, "work in a temporary git worktree instead of switching branches in the target (optional)")
+	listFiles := flag.Bool("list", false, "print the files that would be offered for selection and exit")
+	printBranch := flag.Bool("print-branch", false, "print the branch name generated for the selected file and exit")
+	toStdout := flag.Bool("stdout", false, "print the selected file's contents to stdout instead of copying it, and exit")
+	verbose := flag.Bool("v", false, "verbose output: print each command and resolved path")
+	quiet := flag.Bool("q", false, "quiet output: only print errors")
+	flag.DurationVar(&commandTimeout, "command-timeout", 0, "stop any single git or gh command running longer than this, e.g. 2m (optional)")
+	totalTimeout := flag.Duration("total-timeout", 0, "stop the whole run after this long, e.g. 10m (optional)")
+	flag.BoolVar(&fzfTimeout, "fzf-timeout", false, "apply -command-timeout to fzf as well (optional)")
+	fzfHeight := flag.String("fzf-height", "", "height of the fzf window, e.g. 100% for fullscreen (optional) (default 40%, or the --height in FZF_DEFAULT_OPTS)")
+	fzfExtra := flag.String("fzf-args", "", "extra arguments for fzf, quoted like a shell command line, e.g. \"--bind 'ctrl-a:select-all'\" (optional)")
+	flag.BoolVar(&showCommands, "show-commands", false, "print each git, gh and fzf command line to stderr before running it (optional)")
+	showStats := flag.Bool("stats", false, "print how long the walk, fzf, copying and each git and gh command took to stderr at the end (optional)")
+	noColor := flag.Bool("no-color", false, "disable colored output (also honors NO_COLOR)")
+
+	flag.Usage = usage
+
+	// load defaults from config files and the environment before parsing flags ⚙️
+	// a bad default is only reported after -version and -completion had
+	// their chance, since neither needs any of them
+	var defaultsErr error
+	if err := loadConfig(); err != nil {
+		defaultsErr = fmt.Errorf("error loading config: %w", err)
+	} else if err := loadEnv(); err != nil {
+		defaultsErr = fmt.Errorf("error loading environment: %w", err)
+	}
+
+	flag.Parse()
+
+	// print shell completion script 🐚
+	if *completion != "" {
+		if err := generateCompletion(*completion, os.Stdout); err != nil {
+			return fmt.Errorf("error: %w", err)
+		}
+		return nil
+	}
+
+	// print version info 🏷️
+	if *showVersion {
+		fmt.Printf("elf-owl %s (commit %s, built %s)\n", version, commit, date)
+		return nil
+	}
+
+	if defaultsErr != nil {
+		return defaultsErr
+	}
+
+	// elf-owl <file>... names the files instead of picking them in fzf
+	fileArgs := flag.Args()
+	if len(fileArgs) > 1 && !*separatePRs {
+		*multi = true
+	}
+
+	// set the log level 📢
+	if *verbose && *quiet {
+		return fmt.Errorf("error: -v and -q cannot be used together")
+	}
+	if *verbose {
+		currentLevel = levelVerbose
+	} else if *quiet || *toStdout {
+		// -stdout output is the file, so keep everything else out of it
+		currentLevel = levelQuiet
+	}
+	setupColor(*noColor)
+
+	// time every phase from here on ⏱️
+	if *showStats {
+		phaseTimes = newPhaseStats()
+		defer phaseTimes.print(os.Stderr)
+	}
+
+	// bound the whole run ⏰
+	if *totalTimeout < 0 || commandTimeout < 0 {
+		return fmt.Errorf("error: -total-timeout and -command-timeout must be positive durations")
+	}
+	if *totalTimeout > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(context.Background(), *totalTimeout)
+		defer cancel()
+	}
+
+	// expand ~ and env vars, since config and env values skip the shell 🏠
+	*targetDir = expandPath(*targetDir)
+	*bodyFile = expandPath(*bodyFile)
+	*commitBodyFile = expandPath(*commitBodyFile)
+	*manifestPath = expandPath(*manifestPath)
+
+	// validate required flags
+	if len(searchDirs.values) == 0 && len(fileArgs) == 0 {
+		flag.Usage()
+		return fmt.Errorf("error: search directory is required")
+	}
+
+	var absSearchDirs []string
+	seenSearchDirs := make(map[string]bool)
+	for _, searchDir := range searchDirs.values {
+		searchDir = expandPath(searchDir)
+
+		// validate that searchdir exists 🔍
+		if _, err := os.Stat(searchDir); os.IsNotExist(err) {
+			return fmt.Errorf("error: search directory '%s' does not exist", searchDir)
+		}
+
+		// convert paths to absolute ✨
+		absSearchDir, err := filepath.Abs(searchDir)
+		if err != nil {
+			return fmt.Errorf("error getting absolute path: %w", err)
+		}
+		if !seenSearchDirs[absSearchDir] {
+			seenSearchDirs[absSearchDir] = true
+			absSearchDirs = append(absSearchDirs, absSearchDir)
+		}
+		logVerbose("search directory: %s", absSearchDir)
+	}
+	absTargetDir, err := filepath.Abs(*targetDir)
+	if err != nil {
+		return fmt.Errorf("error getting absolute path: %w", err)
+	}
+	logVerbose("target directory: %s", absTargetDir)
+
+	// bootstrap a new repo when the target isn't one yet 🐣
+	if *remoteURL != "" && !*initRepoFlag {
+		return fmt.Errorf("error: -remote-url requires -init-repo")
+	}
+	needsInit := *initRepoFlag && !isGitRepo(absTargetDir)
+	if needsInit && *remoteURL == "" {
+		if *autoMerge || *pushOnly {
+			return fmt.Errorf("error: -auto-merge and -push-only need a remote, pass -remote-url with -init-repo")
+		}
+		if !*noPR && !*noCommit {
+			logInfo("the new repo has no remote, so changes will be committed without pushing or opening a pr")
+			*noPR = true
+		}
+	}
+
+	if *bodyFile != "" && *bodyEdit {
+		return fmt.Errorf("error: -body-file and -body-edit cannot be used together")
+	}
+
+	if *commitPerFile && !*multi && !*selectAll {
+		return fmt.Errorf("error: -commit-per-file requires -multi or -all")
+	}
+
+	if *separatePRs && (*commitPerFile || *branchName != "" || *editBranch) {
+		return fmt.Errorf("error: -separate-prs cannot be combined with -commit-per-file, -branch or -edit-branch")
+	}
+
+	if *noCommit && (*noPR || *autoMerge || *separatePRs || *commitPerFile) {
+		return fmt.Errorf("error: -no-commit only stages files and cannot be combined with -no-pr, -auto-merge, -separate-prs or -commit-per-file")
+	}
+
+	if *useWorktree && *noCommit {
+		return fmt.Errorf("error: -no-commit would stage files in a worktree that gets removed, drop -worktree")
+	}
+
+	if *noPR && *autoMerge {
+		return fmt.Errorf("error: -auto-merge needs a pr and cannot be used with -no-pr")
+	}
+
+	if *updateStrategy != "rebase" && *updateStrategy != "merge" {
+		return fmt.Errorf("error: invalid -update-strategy '%s' (want rebase or merge)", *updateStrategy)
+	}
+
+	if *update && (*noPR || *noCommit) {
+		return fmt.Errorf("error: -update runs before pushing and cannot be used with -no-pr or -no-commit")
+	}
+
+	if *fetch && *from == "" {
+		return fmt.Errorf("error: -fetch requires -from")
+	}
+
+	if *from != "" && *noCommit {
+		return fmt.Errorf("error: -no-commit stays on the current branch and cannot be used with -from")
+	}
+
+	if len(fileArgs) > 0 && (*watch || *listFiles || *selectAll) {
+		return fmt.Errorf("error: file arguments cannot be combined with -watch, -list or -all")
+	}
+
+	if *toStdout && (*watch || *listFiles || *printBranch || *separatePRs) {
+		return fmt.Errorf("error: -stdout cannot be combined with -watch, -list, -print-branch or -separate-prs")
+	}
+
+	// new files are picked up unattended, one pr each 👀
+	if *watch {
+		if !*yes {
+			return fmt.Errorf("error: -watch runs unattended and requires -yes")
+		}
+		if *multi || *selectAll || *separatePRs || *listFiles || *printBranch {
+			return fmt.Errorf("error: -watch picks its own files and cannot be combined with -multi, -all, -separate-prs, -list or -print-branch")
+		}
+		if *noCommit || *commitPerFile || *branchName != "" || *editBranch || *bodyEdit {
+			return fmt.Errorf("error: -watch cannot be combined with -no-commit, -commit-per-file, -branch, -edit-branch or -body-edit")
+		}
+		for _, searchDir := range absSearchDirs {
+			if isWithinDir(searchDir, absTargetDir) {
+				return fmt.Errorf("error: -watch would pick up its own copies, the target is inside '%s'", searchDir)
+			}
+		}
+	}
+
+	if *labelFromPath {
+		if *labelSegment < 1 {
+			return fmt.Errorf("error: -label-segment counts from 1, got %d", *labelSegment)
+		}
+		if *noPR || *noCommit || *pushOnly {
+			return fmt.Errorf("error: -label-from-path labels the pr and cannot be used with -no-pr, -no-commit or -push-only")
+		}
+	}
+
+	if *pushOnly && (*noPR || *noCommit || *autoMerge) {
+		return fmt.Errorf("error: -push-only cannot be combined with -no-pr, -no-commit or -auto-merge")
+	}
+
+	// the new name must stay a plain file name inside the target ✏️
+	if *rename != "" {
+		if strings.ContainsRune(*rename, '/') || strings.ContainsRune(*rename, filepath.Separator) ||
+			*rename == "." || *rename == ".." {
+			return fmt.Errorf("error: -rename must be a plain file name, got '%s'", *rename)
+		}
+		if *multi || *separatePRs || *selectAll {
+			return fmt.Errorf("error: -rename can only be used when copying a single file")
+		}
+	}
+
+	// the subdirectory has to stay inside the target 🛡️
+	if *destSubdir != "" {
+		if filepath.IsAbs(*destSubdir) || !isWithinDir(absTargetDir, filepath.Join(absTargetDir, *destSubdir)) {
+			return fmt.Errorf("error: -dest-subdir '%s' must be a relative path inside the target directory", *destSubdir)
+		}
+		*destSubdir = filepath.Clean(*destSubdir)
+	}
+
+	if *keepParents < 0 {
+		return fmt.Errorf("error: -keep-parents can't be negative")
+	}
+
+	// parse the hook now so a typo fails before anything is copied 🪝
+	postCopyArgs, err := splitArgs(*postCopyHook)
+	if err != nil {
+		return fmt.Errorf("error: invalid -post-copy-hook: %w", err)
+	}
+
+	prePRArgs, err := splitArgs(*prePRHook)
+	if err != nil {
+		return fmt.Errorf("error: invalid -pre-pr-hook: %w", err)
+	}
+
+	fzfExtraArgs, err := splitArgs(*fzfExtra)
+	if err != nil {
+		return fmt.Errorf("error: invalid -fzf-args: %w", err)
+	}
+	fzfOpts := fzfOptions{height: *fzfHeight, extraArgs: fzfExtraArgs}
+
+	branchCmdArgs, err := splitArgs(*branchCmd)
+	if err != nil {
+		return fmt.Errorf("error: invalid -branch-cmd: %w", err)
+	}
+	if len(branchCmdArgs) > 0 && *branchName != "" {
+		return fmt.Errorf("error: -branch-cmd and -branch cannot be used together")
+	}
+
+	if *ghRepo != "" {
+		if owner, name, ok := strings.Cut(*ghRepo, "/"); !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+			return fmt.Errorf("error: -gh-repo must look like owner/name, got '%s'", *ghRepo)
+		}
+	}
+	if *remote == "" {
+		return fmt.Errorf("error: -remote can't be empty")
+	}
+
+	switch *onExists {
+	case "":
+		// re-running for an amended file adds a commit to its open pr ♻️
+		*onExists = "switch"
+		if *noReuse {
+			*onExists = "fail"
+		}
+	case "fail", "suffix", "force", "switch":
+	default:
+		return fmt.Errorf("error: invalid -on-exists '%s' (want fail, suffix, force or switch)", *onExists)
+	}
+
+	switch *mergeMethod {
+	case "squash", "merge", "rebase":
+	default:
+		return fmt.Errorf("error: invalid merge method '%s' (want squash, merge or rebase)", *mergeMethod)
+	}
+
+	// a rebase merge replays the commits, so there's no commit to name 📝
+	if *mergeSubject != "" || *mergeBody != "" {
+		if !*autoMerge {
+			return fmt.Errorf("error: -merge-subject and -merge-body require -auto-merge")
+		}
+		if *mergeMethod == "rebase" {
+			return fmt.Errorf("error: -merge-subject and -merge-body need -merge-method squash or merge, a rebase makes no merge commit")
+		}
+	}
+
+	// collect pr assignees 👤
+	var assignees []string
+	if *assignSelf {
+		assignees = append(assignees, "@me")
+	}
+	for _, login := range strings.Split(*assignee, ",") {
+		if login = strings.TrimSpace(login); login != "" {
+			assignees = append(assignees, login)
+		}
+	}
+
+	// validate the commit author override ✍️
+	author := ""
+	if *authorName != "" || *authorEmail != "" {
+		if *authorName == "" || *authorEmail == "" {
+			return fmt.Errorf("error: -author-name and -author-email must be used together")
+		}
+		if !validEmail(*authorEmail) {
+			return fmt.Errorf("error: invalid author email '%s'", *authorEmail)
+		}
+		author = fmt.Sprintf("%s <%s>", *authorName, *authorEmail)
+	}
+
+	// validate that bodyfile exists 📄
+	absBodyFile := ""
+	if *bodyFile != "" {
+		if _, err := os.Stat(*bodyFile); err != nil {
+			return fmt.Errorf("error: body file '%s' not found: %w", *bodyFile, err)
+		}
+		absBodyFile, err = filepath.Abs(*bodyFile)
+		if err != nil {
+			return fmt.Errorf("error getting absolute path: %w", err)
+		}
+	}
+
+	// read the commit message body 📝
+	if *commitBody != "" && *commitBodyFile != "" {
+		return fmt.Errorf("error: -commit-body and -commit-body-file cannot be used together")
+	}
+	if *commitBodyFile != "" {
+		content, err := os.ReadFile(*commitBodyFile)
+		if err != nil {
+			return fmt.Errorf("error reading commit body file: %w", err)
+		}
+		*commitBody = string(content)
+	}
+	*commitBody = strings.TrimSpace(*commitBody)
+
+	if err := validateCommitType(*commitType, *commitScope); err != nil {
+		return fmt.Errorf("error: %w", err)
+	}
+
+	if len(expandVars.values) > 0 && !*expand {
+		return fmt.Errorf("error: -var requires -expand")
+	}
+
+	if *manifestAppend && *manifestPath == "" {
+		return fmt.Errorf("error: -manifest-append requires -manifest")
+	}
+
+	if _, err := filepath.Match(*pattern, ""); err != nil {
+		return fmt.Errorf("error: invalid -pattern '%s': %w", *pattern, err)
+	}
+
+	// only list recently modified files ⏱️
+	var modifiedAfter time.Time
+	if *since < 0 {
+		return fmt.Errorf("error: -since must be a positive duration")
+	} else if *since > 0 {
+		modifiedAfter = time.Now().Add(-*since)
+	}
+
+	if *maxFiles < 0 {
+		return fmt.Errorf("error: -max-files can't be negative, use 0 for no limit")
+	}
+
+	if maxSize > 0 && minSize > maxSize {
+		return fmt.Errorf("error: -min-size %s is larger than -max-size %s", formatSize(int64(minSize)), formatSize(int64(maxSize)))
+	}
+
+	// verify required commands exist, reporting every missing one at once 🛠️
+	var requiredCommands []string
+	if !*listFiles && !*selectAll && !*watch && len(fileArgs) == 0 {
+		requiredCommands = append(requiredCommands, "fzf")
+	}
+	if !*printBranch && !*listFiles && !*toStdout {
+		requiredCommands = append(requiredCommands, "git")
+		if !*noPR && !*noCommit && !*pushOnly {
+			requiredCommands = append(requiredCommands, "gh")
+		}
+	}
+	if missing := missingCommands(requiredCommands); len(missing) > 0 {
+		lines := []string{"error: required commands not found in path:"}
+		for _, cmd := range missing {
+			lines = append(lines, fmt.Sprintf("  %s: install from %s", cmd, installHints[cmd]))
+		}
+		return withExitCode(exitMissingCommand, errors.New(strings.Join(lines, "\n")))
+	}
+
+	// fail before picking files if gh can't open the pr at the end 🔑
+	if slices.Contains(requiredCommands, "gh") {
+		if err := checkGhAuth(absTargetDir, *remote); err != nil {
+			return withExitCode(exitGitFailure, fmt.Errorf("error: %w", err))
+		}
+	}
+
+	// find all files in the search directories
+	stats := &filterStats{}
+	findOpts := findOptions{
+		followSymlinks: *followSymlinks,
+		concurrent:     *concurrent,
+		modifiedAfter:  modifiedAfter,
+		minSize:        int64(minSize),
+		maxSize:        int64(maxSize),
+		pattern:        *pattern,
+		stats:          stats,
+	}
+	if *maxFiles > 0 {
+		findOpts.limit = &fileLimit{max: *maxFiles}
+	}
+	var selectedFiles []searchFile
+	if len(fileArgs) > 0 {
+		// files named on the command line skip the walk and fzf 📌
+		selectedFiles, err = resolveFileArgs(fileArgs, absSearchDirs)
+		if err != nil {
+			return fmt.Errorf("error: %w", err)
+		}
+	} else {
+		files, err := findSearchFiles(absSearchDirs, findOpts)
+		if errors.Is(err, errTooManyFiles) {
+			return fmt.Errorf("error: more than %d files in '%s', point -search somewhere narrower, filter with -pattern, -since or -max-size, or raise -max-files",
+				*maxFiles, strings.Join(absSearchDirs, "', '"))
+		}
+		if err != nil {
+			return fmt.Errorf("error finding files: %w", err)
+		}
+
+		logVerbose("%s", stats.summary())
+
+		// just print what would be offered to fzf 📜
+		if *listFiles {
+			for _, file := range files {
+				fmt.Println(file.display)
+			}
+			return nil
+		}
+
+		// a watch starts out empty and waits for files to show up
+		if !*watch {
+			if len(files) == 0 {
+				return fmt.Errorf("no files found in search directory '%s'", strings.Join(absSearchDirs, "', '"))
+			}
+			selectedFiles, err = selectFiles(files, *selectAll, *multi || *separatePRs, *yes, fzfOpts)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// show the selection instead of copying it 🐈
+	if *toStdout {
+		return catFiles(selectedFiles)
+	}
+
+	// just show the branch names the selection would get 🌿
+	if *printBranch {
+		for _, selectedFile := range selectedFiles {
+			name, err := branchNameFor(branchCmdArgs, absTargetDir, selectedFile.rel)
+			if err != nil {
+				return fmt.Errorf("error: %w", err)
+			}
+			fmt.Println(name)
+		}
+		return nil
+	}
+
+	if needsInit {
+		if err := initRepo(absTargetDir, *remote, *remoteURL); err != nil {
+			return withExitCode(exitGitFailure, fmt.Errorf("error: %w", err))
+		}
+	}
+
+	// compose the pr body ✏️
+	finalBodyFile := absBodyFile
+	// undoes temp state (body file, worktree) before exiting 🧹
+	cleanup := func() {}
+	if finalBodyFile == "" && !*noPR && !*noCommit && !*pushOnly {
+		// the repo's own template goes after the emoji line 📄
+		template := ""
+		if *usePRTemplate {
+			template, err = findPRTemplate(absTargetDir)
+			if err != nil {
+				return fmt.Errorf("error reading pr template: %w", err)
+			}
+			if template != "" {
+				template = defaultBody() + "\n\n" + template
+			}
+		}
+
+		switch {
+		case *bodyEdit:
+			finalBodyFile, err = composeBodyInEditor(template)
+		case template != "":
+			finalBodyFile, err = writeBody(template)
+		default:
+			finalBodyFile, err = writeBody(defaultBody())
+		}
+		if err != nil {
+			return fmt.Errorf("error preparing pr body: %w", err)
+		}
+		tmpBody := finalBodyFile
+		cleanup = func() { os.Remove(tmpBody) }
+	}
+
+	opts := gitOptions{
+		targetDir:          absTargetDir,
+		bodyFile:           finalBodyFile,
+		commitPerFile:      *commitPerFile,
+		autoMerge:          *autoMerge,
+		mergeMethod:        *mergeMethod,
+		mergeSubject:       *mergeSubject,
+		mergeBody:          *mergeBody,
+		assignees:          assignees,
+		sign:               *sign,
+		signoff:            *signoff,
+		author:             author,
+		noReuse:            *noReuse,
+		noPR:               *noPR,
+		pushOnly:           *pushOnly,
+		noCommit:           *noCommit,
+		prePRHook:          prePRArgs,
+		noRollback:         *noRollback,
+		deleteRemoteOnFail: *deleteRemoteOnFail,
+		commitBody:         *commitBody,
+		commitType:         *commitType,
+		commitScope:        *commitScope,
+		from:               *from,
+		fetch:              *fetch,
+		base:               *base,
+		update:             *update,
+		updateStrategy:     *updateStrategy,
+		branchCmd:          branchCmdArgs,
+		onExists:           *onExists,
+		remote:             *remote,
+		ghRepo:             *ghRepo,
+		previewDiff:        *previewDiffFlag,
+		yes:                *yes,
+		labelSegment:       labelSegmentFor(*labelFromPath, *labelSegment),
+	}
+
+	// work in a throwaway worktree instead of the user's checkout 🌳
+	if *useWorktree {
+		wt, err := createWorktree(absTargetDir)
+		if err != nil {
+			cleanup()
+			return withExitCode(exitGitFailure, fmt.Errorf("error: %w", err))
+		}
+		removeBody := cleanup
+		cleanup = func() {
+			wt.remove()
+			removeBody()
+		}
+		opts.targetDir = wt.targetDir
+	}
+
+	copyOpts := copyOptions{
+		targetDir:      opts.targetDir,
+		followSymlinks: *followSymlinks,
+		rename:         *rename,
+		destSubdir:     *destSubdir,
+		keepParents:    *keepParents,
+		postCopyHook:   postCopyArgs,
+		textOnly:       *textOnly,
+		checkSpace:     *checkSpaceFlag,
+		preserveXattrs: *preserveXattrs,
+	}
+	if *expand {
+		copyOpts.expandVars = defaultVars(*authorName)
+		if copyOpts.expandVars["AUTHOR"] == "" {
+			copyOpts.expandVars["AUTHOR"], _ = commandOutput(opts.targetDir, "git", "config", "user.name")
+		}
+		for key, value := range expandVars.values {
+			copyOpts.expandVars[key] = value
+		}
+	}
+	if *manifestPath != "" {
+		copyOpts.manifest = &manifest{
+			path:       *manifestPath,
+			appendMode: *manifestAppend,
+			workDir:    opts.targetDir,
+			targetDir:  absTargetDir,
+		}
+	}
+
+	// one branch and pr per new file, until interrupted 👀
+	if *watch {
+		err := watchFiles(absSearchDirs, findOpts, opts, copyOpts)
+		cleanup()
+		if copyOpts.manifest != nil {
+			if mErr := copyOpts.manifest.write(); mErr != nil && err == nil {
+				err = fmt.Errorf("error: %w", mErr)
+			}
+		}
+		return err
+	}
+
+	// one branch and pr per file 🔁
+	if *separatePRs {
+		urls, ok := runSeparatePRs(opts, copyOpts, selectedFiles)
+		cleanup()
+		var manifestErr error
+		if copyOpts.manifest != nil {
+			manifestErr = copyOpts.manifest.write()
+		}
+		if *clipboard && len(urls) > 0 {
+			copyURLs(urls)
+		}
+		if !ok {
+			if manifestErr != nil {
+				logError("error: %v", manifestErr)
+			}
+			return withExitCode(exitGitFailure, fmt.Errorf("error: not every pr went through, see the summary above"))
+		}
+		if manifestErr != nil {
+			return fmt.Errorf("error: %w", manifestErr)
+		}
+		logSuccess("successfully completed all operations! 🎉")
+		return nil
+	}
+
+	// generate branch name if not provided 🌿
+	opts.branchName = *branchName
+	if opts.branchName == "" {
+		opts.branchName, err = branchNameFor(opts.branchCmd, opts.targetDir, selectedFiles[0].rel)
+		if err != nil {
+			cleanup()
+			return fmt.Errorf("error: %w", err)
+		}
+	}
+	// let the user tweak the name before anything is committed ✏️
+	if *editBranch && !*yes && isInteractive() {
+		edited, err := promptLine("branch name", opts.branchName)
+		if err != nil {
+			cleanup()
+			return fmt.Errorf("error: %w", err)
+		}
+		if edited != opts.branchName {
+			if opts.branchName, err = checkBranchName(opts.targetDir, edited); err != nil {
+				cleanup()
+				return fmt.Errorf("error: branch name %w", err)
+			}
+		}
+	}
+	logVerbose("branch name: %s", opts.branchName)
+
+	// copy the files 📋
+	if copyOpts.expandVars != nil {
+		copyOpts.expandVars = withBranch(copyOpts.expandVars, opts.branchName)
+	}
+	opts.files, err = copySelected(copyOpts, selectedFiles)
+	if err != nil {
+		cleanup()
+		return fmt.Errorf("error copying file: %w", err)
+	}
+	if copyOpts.manifest != nil {
+		if err := copyOpts.manifest.write(); err != nil {
+			cleanup()
+			return fmt.Errorf("error: %w", err)
+		}
+	}
+
+	// keep track of what happened for the summary 🧾
+	summary := &runSummary{pushOnly: *pushOnly, noPR: *noPR}
+	for i, file := range selectedFiles {
+		summary.sources = append(summary.sources, filepath.Join(file.root, file.rel))
+		summary.dests = append(summary.dests, inTargetDir(opts.files[i], opts.targetDir, absTargetDir))
+	}
+	opts.summary = summary
+	if opts.labelSegment > 0 {
+		opts.labels = pathLabels(selectedFiles, opts.labelSegment)
+	}
+
+	// perform git operations 🔄
+	logInfo("performing git operations...")
+	prURL, err := gitOperations(opts)
+	cleanup()
+	if errors.Is(err, errNoChanges) {
+		logInfo("no changes to commit — skipping PR")
+		return nil
+	}
+	if err != nil {
+		return withExitCode(exitGitFailure, fmt.Errorf("error in git operations: %w", err))
+	}
+
+	// ready to paste into the tracker 📋
+	if *clipboard && prURL != "" {
+		copyURLs([]string{lastLine(prURL)})
+	}
+
+	summary.prURL = lastLine(prURL)
+	summary.print()
+	logSuccess("successfully completed all operations! 🎉")
+	return nil
 }
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestIsWithinDir(t *testing.T) {
+	tests := []struct {
+		dir, path string
+		want      bool
+	}{
+		{"/a/b", "/a/b", true},
+		{"/a/b", "/a/b/c", true},
+		{"/a/b", "/a/b/c/d.md", true},
+		{"/a/b", "/a/b/x/../y", true},
+		{"/a/b", "/a/b/..foo", true},
+		{"/a/b", "/a", false},
+		{"/a/b", "/a/b/..", false},
+		{"/a/b", "/a/b/../c", false},
+		{"/a/b", "/a/bc", false},
+		{"/a/b", "/a/bc/d", false},
+		{"/a/b", "/x/y", false},
+		{"/a/b", "b", false},
+		{".", "..", false},
+		{".", "../x", false},
+		{".", "x", true},
+		{"a", "a/../..", false},
+		{"a", "a/b/../c", true},
+	}
+	for _, tt := range tests {
+		if got := isWithinDir(tt.dir, tt.path); got != tt.want {
+			t.Errorf("isWithinDir(%q, %q) = %v, want %v", tt.dir, tt.path, got, tt.want)
+		}
+	}
+}
+
+// makes a search dir holding report.md and an empty target dir
+func copyDirs(t *testing.T) (string, string) {
+	t.Helper()
+	base := t.TempDir()
+	root := filepath.Join(base, "search")
+	target := filepath.Join(base, "target")
+	for _, dir := range []string{root, target} {
+		if err := os.Mkdir(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(root, "report.md"), []byte("finding\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(base, "outside.md"), []byte("secret\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return root, target
+}
+
+func TestCopySelected(t *testing.T) {
+	root, target := copyDirs(t)
+	dests, err := copySelected(copyOptions{targetDir: target}, []searchFile{{root: root, rel: "report.md", display: "report.md"}})
+	if err != nil {
+		t.Fatalf("copySelected: %v", err)
+	}
+	if want := filepath.Join(target, "report.md"); len(dests) != 1 || dests[0] != want {
+		t.Fatalf("copySelected = %v, want [%s]", dests, want)
+	}
+	if content, err := os.ReadFile(dests[0]); err != nil || string(content) != "finding\n" {
+		t.Errorf("copied content = %q, %v", content, err)
+	}
+}
+
+func TestCopySelectedRejectsTraversal(t *testing.T) {
+	tests := []struct {
+		name    string
+		rel     string
+		subdir  string
+		wantErr string
+	}{
+		{"source rel", "../outside.md", "", "outside the search directory"},
+		{"nested source rel", "sub/../../outside.md", "", "outside the search directory"},
+		{"dest subdir", "report.md", "../escape", "outside the target directory"},
+		{"nested dest subdir", "report.md", "a/../../escape", "outside the target directory"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, target := copyDirs(t)
+			opts := copyOptions{targetDir: target, destSubdir: tt.subdir}
+			_, err := copySelected(opts, []searchFile{{root: root, rel: tt.rel, display: tt.rel}})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("copySelected(%q, subdir %q) error = %v, want %q", tt.rel, tt.subdir, err, tt.wantErr)
+			}
+			if _, err := os.Stat(filepath.Join(filepath.Dir(target), "escape")); !os.IsNotExist(err) {
+				t.Errorf("something was written outside the target")
+			}
+		})
+	}
+}
+
+func TestCopySelectedRemovesCopiesOnHookFailure(t *testing.T) {
+	root, target := copyDirs(t)
+	opts := copyOptions{targetDir: target, postCopyHook: []string{"false"}}
+	if _, err := copySelected(opts, []searchFile{{root: root, rel: "report.md", display: "report.md"}}); err == nil {
+		t.Fatal("copySelected with a failing post-copy hook succeeded")
+	}
+	if _, err := os.Lstat(filepath.Join(target, "report.md")); !os.IsNotExist(err) {
+		t.Errorf("the copy was left behind after the hook failed")
+	}
+}
+
+func TestSplitArgs(t *testing.T) {
+	tests := []struct {
+		s    string
+		want []string
+	}{
+		{"", nil},
+		{"  \t\n", nil},
+		{"prettier --write {file}", []string{"prettier", "--write", "{file}"}},
+		{"  a   b  ", []string{"a", "b"}},
+		{`echo 'a b' "c d"`, []string{"echo", "a b", "c d"}},
+		{`'it''s'`, []string{"its"}},
+		{`'a "b" \c'`, []string{`a "b" \c`}},
+		{`"say \"hi\" \\ \$HOME \` + "`x`" + `"`, []string{`say "hi" \ $HOME ` + "`x`"}},
+		{`"a\b \n"`, []string{`a\b \n`}},
+		{`a\ b c\"d`, []string{"a b", `c"d`}},
+		{`a\`, []string{"a"}},
+		{`\`, []string{""}},
+		{`'' "" x`, []string{"", "", "x"}},
+		{`--bind 'ctrl-a:select-all'`, []string{"--bind", "ctrl-a:select-all"}},
+		{`pre"mid"'post'`, []string{"premidpost"}},
+	}
+	for _, tt := range tests {
+		got, err := splitArgs(tt.s)
+		if err != nil || !slices.Equal(got, tt.want) {
+			t.Errorf("splitArgs(%q) = %q, %v, want %q", tt.s, got, err, tt.want)
+		}
+	}
+}
+
+func TestSplitArgsUnterminated(t *testing.T) {
+	tests := []struct {
+		s       string
+		wantErr string
+	}{
+		{`echo 'a b`, "unterminated single quote"},
+		{`'`, "unterminated single quote"},
+		{`echo "a b`, "unterminated double quote"},
+		{`"a\"`, "unterminated double quote"},
+		{`"`, "unterminated double quote"},
+	}
+	for _, tt := range tests {
+		if _, err := splitArgs(tt.s); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("splitArgs(%q) error = %v, want %q", tt.s, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// one copied file in the manifest 🧾
+type manifestEntry struct {
+	Source   string `json:"source"`
+	Dest     string `json:"dest"`
+	Size     int64  `json:"size"`
+	SHA256   string `json:"sha256"` // empty for a copied symlink that dangles
+	CopiedAt string `json:"copied_at"`
+}
+
+// collects copied files and writes them as json, or csv when the path
+// ends in .csv, for audit trails 📒
+type manifest struct {
+	path       string
+	appendMode bool   // add to an existing manifest instead of replacing it
+	workDir    string // where copies are made, a -worktree or targetDir
+	targetDir  string // where dests are recorded as being
+	entries    []manifestEntry
+}
+
+// records a finished copy, hashing the destination as it ended up
+func (m *manifest) record(source, dest string) error {
+	entry := manifestEntry{
+		Source:   source,
+		Dest:     inTargetDir(dest, m.workDir, m.targetDir),
+		CopiedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+
+	file, err := os.Open(dest)
+	if err == nil {
+		defer file.Close()
+		hash := sha256.New()
+		entry.Size, err = io.Copy(hash, file)
+		if err != nil {
+			return fmt.Errorf("failed to hash %s: %w", dest, err)
+		}
+		entry.SHA256 = hex.EncodeToString(hash.Sum(nil))
+	} else if info, lerr := os.Lstat(dest); lerr != nil || info.Mode()&os.ModeSymlink == 0 {
+		return fmt.Errorf("failed to hash %s: %w", dest, err)
+	}
+
+	m.entries = append(m.entries, entry)
+	return nil
+}
+
+func (m *manifest) isCSV() bool {
+	return strings.EqualFold(filepath.Ext(m.path), ".csv")
+}
+
+// writes the recorded entries, after any already in the file in append mode
+func (m *manifest) write() error {
+	if m.isCSV() {
+		return m.writeCSV()
+	}
+	return m.writeJSON()
+}
+
+func (m *manifest) writeJSON() error {
+	var entries []manifestEntry
+	if m.appendMode {
+		content, err := os.ReadFile(m.path)
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to read manifest: %w", err)
+		}
+		if len(strings.TrimSpace(string(content))) > 0 {
+			if err := json.Unmarshal(content, &entries); err != nil {
+				return fmt.Errorf("can't append to manifest %s: %w", m.path, err)
+			}
+		}
+	}
+	entries = append(entries, m.entries...)
+
+	content, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(m.path, append(content, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	return nil
+}
+
+func (m *manifest) writeCSV() error {
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if m.appendMode {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	}
+	file, err := os.OpenFile(m.path, flags, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open manifest: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to open manifest: %w", err)
+	}
+
+	w := csv.NewWriter(file)
+	// an appended-to manifest already has its header
+	if info.Size() == 0 {
+		w.Write([]string{"source", "dest", "size", "sha256", "copied_at"})
+	}
+	for _, e := range m.entries {
+		w.Write([]string{e.Source, e.Dest, strconv.FormatInt(e.Size, 10), e.SHA256, e.CopiedAt})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// reports whether stdin is a terminal someone can answer prompts on
+func isInteractive() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// asks for a line on stdin, showing def as the answer an empty line keeps ⌨️
+func promptLine(label, def string) (string, error) {
+	line, err := readAnswer(fmt.Sprintf("%s [%s]: ", label, def))
+	if err != nil {
+		return "", err
+	}
+	if line == "" {
+		return def, nil
+	}
+	return line, nil
+}
+
+// prints prompt and reads one trimmed line from stdin
+func readAnswer(prompt string) (string, error) {
+	fmt.Fprint(os.Stdout, colorize(ansiYellow, prompt))
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("failed to read answer: %w", err)
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// asks a yes/no question on stdin, anything but y or yes meaning no ❓
+func confirm(question string) (bool, error) {
+	answer, err := readAnswer(question + " [y/N]: ")
+	if err != nil {
+		return false, err
+	}
+	answer = strings.ToLower(answer)
+	return answer == "y" || answer == "yes", nil
+}
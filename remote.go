@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// where a remote lives, e.g. github.com or a github enterprise host 🐙
+type remoteRepo struct {
+	host  string
+	owner string
+	repo  string
+}
+
+// parses an ssh (git@host:owner/repo.git, ssh://git@host/owner/repo) or
+// https (https://host/owner/repo) remote url, dropping any .git suffix,
+// trailing slash, user or port
+func parseRemoteURL(remote string) (remoteRepo, error) {
+	remote = strings.TrimSpace(remote)
+
+	host, path := "", ""
+	if strings.Contains(remote, "://") {
+		u, err := url.Parse(remote)
+		if err != nil {
+			return remoteRepo{}, fmt.Errorf("invalid remote url '%s': %w", remote, err)
+		}
+		switch u.Scheme {
+		case "https", "http", "ssh", "git":
+		default:
+			return remoteRepo{}, fmt.Errorf("unsupported remote url '%s'", remote)
+		}
+		host, path = u.Hostname(), u.Path
+	} else {
+		// scp-like syntax, [user@]host:path
+		hostPart, pathPart, ok := strings.Cut(remote, ":")
+		if !ok || strings.Contains(hostPart, "/") {
+			return remoteRepo{}, fmt.Errorf("unsupported remote url '%s'", remote)
+		}
+		if _, h, hasUser := strings.Cut(hostPart, "@"); hasUser {
+			hostPart = h
+		}
+		host, path = hostPart, pathPart
+	}
+
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	owner, repo, ok := strings.Cut(path, "/")
+	if host == "" || !ok || owner == "" || repo == "" || strings.Contains(repo, "/") {
+		return remoteRepo{}, fmt.Errorf("can't find host/owner/repo in remote '%s'", remote)
+	}
+	return remoteRepo{host: host, owner: owner, repo: repo}, nil
+}
+
+// reads and parses the url of the named remote of the repo in dir
+func remoteRepoOf(dir, remote string) (remoteRepo, error) {
+	url, err := commandOutput(dir, "git", "remote", "get-url", remote)
+	if err != nil {
+		return remoteRepo{}, fmt.Errorf("failed to read %s remote: %w", remote, err)
+	}
+	return parseRemoteURL(url)
+}
+
+// returns the web page for opening a pr from branch, built from the
+// remote's url so gh isn't needed 🔗
+func compareURL(dir, remote, branch string) (string, error) {
+	r, err := remoteRepoOf(dir, remote)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("https://%s/%s/%s/compare/%s?expand=1", r.host, r.owner, r.repo, branch), nil
+}
@@ -0,0 +1,54 @@
+package main
+
+import "testing"
+
+func TestParseRemoteURL(t *testing.T) {
+	tests := []struct {
+		remote string
+		want   remoteRepo
+	}{
+		{"git@github.com:owner/repo.git", remoteRepo{"github.com", "owner", "repo"}},
+		{"git@github.com:owner/repo", remoteRepo{"github.com", "owner", "repo"}},
+		{"github.com:owner/repo.git", remoteRepo{"github.com", "owner", "repo"}},
+		{"git@ghe.example.com:team/tool.git", remoteRepo{"ghe.example.com", "team", "tool"}},
+		{"ssh://git@github.com/owner/repo.git", remoteRepo{"github.com", "owner", "repo"}},
+		{"ssh://git@ghe.example.com:2222/owner/repo.git", remoteRepo{"ghe.example.com", "owner", "repo"}},
+		{"https://github.com/owner/repo.git", remoteRepo{"github.com", "owner", "repo"}},
+		{"https://github.com/owner/repo", remoteRepo{"github.com", "owner", "repo"}},
+		{"https://github.com/owner/repo/", remoteRepo{"github.com", "owner", "repo"}},
+		{"https://github.com/owner/repo.git/", remoteRepo{"github.com", "owner", "repo"}},
+		{"https://user@ghe.example.com:8443/owner/repo.git", remoteRepo{"ghe.example.com", "owner", "repo"}},
+		{"  https://github.com/owner/repo.git\n", remoteRepo{"github.com", "owner", "repo"}},
+	}
+	for _, tt := range tests {
+		got, err := parseRemoteURL(tt.remote)
+		if err != nil {
+			t.Errorf("parseRemoteURL(%q) error: %v", tt.remote, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseRemoteURL(%q) = %+v, want %+v", tt.remote, got, tt.want)
+		}
+	}
+}
+
+func TestParseRemoteURLMalformed(t *testing.T) {
+	for _, remote := range []string{
+		"",
+		"not a url",
+		"/local/path/repo.git",
+		"file:///local/path/repo.git",
+		"ftp://github.com/owner/repo",
+		"https://github.com/owner",
+		"https://github.com/owner/",
+		"https://github.com/owner/repo/extra",
+		"https:///owner/repo",
+		"git@github.com:",
+		"git@github.com:owner",
+		"git@github.com:/repo.git",
+	} {
+		if got, err := parseRemoteURL(remote); err == nil {
+			t.Errorf("parseRemoteURL(%q) = %+v, want an error", remote, got)
+		}
+	}
+}
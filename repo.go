@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// name of the first branch in a repo made by -init-repo
+const initialBranch = "main"
+
+// reports whether dir is inside a git work tree
+func isGitRepo(dir string) bool {
+	out, err := commandOutput(dir, "git", "rev-parse", "--is-inside-work-tree")
+	return err == nil && out == "true"
+}
+
+// turns dir into a fresh repo with an empty initial commit on main, so
+// later branches have something to branch from and open prs against 🐣
+// with a remote url, it is added under the remote name and main is pushed
+// there
+func initRepo(dir, remote, remoteURL string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create target directory: %w", err)
+	}
+
+	logInfo("initializing a new repo in %s...", dir)
+	if err := runCommand(dir, "git", "init"); err != nil {
+		return fmt.Errorf("failed to init repo: %w", err)
+	}
+	// set the branch name this way since older git has no init -b
+	if err := runCommand(dir, "git", "symbolic-ref", "HEAD", "refs/heads/"+initialBranch); err != nil {
+		return fmt.Errorf("failed to name the initial branch: %w", err)
+	}
+	if err := runCommand(dir, "git", "commit", "--allow-empty", "-m", "Initial commit"); err != nil {
+		return fmt.Errorf("failed to create the initial commit: %w", err)
+	}
+
+	if remoteURL == "" {
+		return nil
+	}
+	if err := runCommand(dir, "git", "remote", "add", remote, remoteURL); err != nil {
+		return fmt.Errorf("failed to add %s remote: %w", remote, err)
+	}
+	if err := runCommand(dir, "git", "push", "--set-upstream", remote, initialBranch); err != nil {
+		return fmt.Errorf("failed to push %s: %w", initialBranch, err)
+	}
+	return nil
+}
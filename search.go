@@ -0,0 +1,142 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// a flag.Value collecting paths from repeated flags or comma / path-list
+// separated values, e.g. -search a -search b or -search a,b 📚
+type stringList struct {
+	values  []string
+	replace bool // the next Set starts a fresh list
+}
+
+func (l *stringList) String() string {
+	return strings.Join(l.values, ",")
+}
+
+func (l *stringList) Set(s string) error {
+	if l.replace {
+		l.values = nil
+		l.replace = false
+	}
+	for _, part := range strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == os.PathListSeparator
+	}) {
+		if part = strings.TrimSpace(part); part != "" {
+			l.values = append(l.values, part)
+		}
+	}
+	return nil
+}
+
+// makes the next Set replace rather than extend the list, so a value from
+// a later config layer overrides an earlier one instead of adding to it
+func (l *stringList) newLayer() {
+	l.replace = true
+}
+
+// a file offered for selection 📄
+type searchFile struct {
+	root    string // absolute search directory the file was found in
+	rel     string // path relative to root
+	display string // what fzf shows, prefixed by the root's label if there are several
+}
+
+// labels each root by its base name, numbering repeats (findings, findings-2)
+// so entries from different roots never look the same in fzf 🏷️
+func rootLabels(roots []string) []string {
+	labels := make([]string, len(roots))
+	used := make(map[string]bool)
+	for i, root := range roots {
+		base := filepath.Base(root)
+		label := base
+		for n := 2; used[label]; n++ {
+			label = fmt.Sprintf("%s-%d", base, n)
+		}
+		used[label] = true
+		labels[i] = label
+	}
+	return labels
+}
+
+// finds the files in every root, tagging each with the root it came from 🔍
+func findSearchFiles(roots []string, opts findOptions) ([]searchFile, error) {
+	defer phaseTimes.track("walk")()
+	labels := rootLabels(roots)
+
+	var files []searchFile
+	for i, root := range roots {
+		rels, err := findFiles(root, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, rel := range rels {
+			display := rel
+			if len(roots) > 1 {
+				display = filepath.Join(labels[i], rel)
+			}
+			files = append(files, searchFile{root: root, rel: rel, display: display})
+		}
+	}
+	return files, nil
+}
+
+// returns the innermost root that path is in
+func rootOf(roots []string, path string) (string, bool) {
+	best := ""
+	for _, root := range roots {
+		if isWithinDir(root, path) && len(root) > len(best) {
+			best = root
+		}
+	}
+	return best, best != ""
+}
+
+// resolves files named on the command line, trying each as given (from
+// the cwd) and then inside each root 📌
+// a file in a root keeps its path below it, any other file becomes its
+// own root, so it's copied the same way as one picked in fzf
+func resolveFileArgs(args, roots []string) ([]searchFile, error) {
+	var files []searchFile
+	for _, arg := range args {
+		candidates := []string{expandPath(arg)}
+		if !filepath.IsAbs(candidates[0]) {
+			for _, root := range roots {
+				candidates = append(candidates, filepath.Join(root, candidates[0]))
+			}
+		}
+
+		path := ""
+		for _, candidate := range candidates {
+			if _, err := os.Lstat(candidate); err == nil {
+				path = candidate
+				break
+			}
+		}
+		if path == "" {
+			return nil, fmt.Errorf("file '%s' not found here or in the search directories", arg)
+		}
+		path, err := filepath.Abs(path)
+		if err != nil {
+			return nil, err
+		}
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			return nil, fmt.Errorf("'%s' is a directory, name the files in it instead", arg)
+		}
+
+		root, ok := rootOf(roots, path)
+		if !ok {
+			root = filepath.Dir(path)
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, searchFile{root: root, rel: rel, display: arg})
+	}
+	return files, nil
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// size units, as powers of 1024 📏
+var sizeUnits = []struct {
+	suffix string
+	bytes  int64
+}{
+	{"TB", 1 << 40}, {"TIB", 1 << 40}, {"T", 1 << 40},
+	{"GB", 1 << 30}, {"GIB", 1 << 30}, {"G", 1 << 30},
+	{"MB", 1 << 20}, {"MIB", 1 << 20}, {"M", 1 << 20},
+	{"KB", 1 << 10}, {"KIB", 1 << 10}, {"K", 1 << 10},
+	{"B", 1},
+}
+
+// parses a human-readable size like 500KB, 1.5MB or 42 (bytes)
+func parseSize(s string) (int64, error) {
+	upper := strings.ToUpper(strings.TrimSpace(s))
+	multiplier := int64(1)
+	for _, unit := range sizeUnits {
+		if strings.HasSuffix(upper, unit.suffix) {
+			upper = strings.TrimSpace(strings.TrimSuffix(upper, unit.suffix))
+			multiplier = unit.bytes
+			break
+		}
+	}
+
+	// ParseFloat takes NaN and Inf too, which no file size is
+	n, err := strconv.ParseFloat(upper, 64)
+	if err != nil || n < 0 || math.IsNaN(n) || math.IsInf(n, 0) {
+		return 0, fmt.Errorf("invalid size '%s' (want e.g. 500KB or 10MB)", s)
+	}
+	// float64(math.MaxInt64) rounds up to 2^63, itself already too big
+	bytes := n * float64(multiplier)
+	if bytes >= float64(math.MaxInt64) {
+		return 0, fmt.Errorf("size '%s' is too big", s)
+	}
+	return int64(bytes), nil
+}
+
+// formats a byte count for humans, e.g. 1.5MB
+func formatSize(n int64) string {
+	for _, unit := range []struct {
+		suffix string
+		bytes  int64
+	}{{"TB", 1 << 40}, {"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}} {
+		if n >= unit.bytes {
+			value := strconv.FormatFloat(float64(n)/float64(unit.bytes), 'f', 1, 64)
+			return strings.TrimSuffix(value, ".0") + unit.suffix
+		}
+	}
+	return fmt.Sprintf("%dB", n)
+}
+
+// a flag.Value holding a size in bytes, 0 meaning unset
+type byteSize int64
+
+func (b *byteSize) String() string {
+	if *b == 0 {
+		return ""
+	}
+	return formatSize(int64(*b))
+}
+
+func (b *byteSize) Set(s string) error {
+	n, err := parseSize(s)
+	if err != nil {
+		return err
+	}
+	*b = byteSize(n)
+	return nil
+}
@@ -0,0 +1,52 @@
+package main
+
+import "testing"
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		s    string
+		want int64
+	}{
+		{"42", 42},
+		{"0", 0},
+		{"500KB", 500 << 10},
+		{"1.5MB", 3 << 19},
+		{" 10 mb ", 10 << 20},
+		{"2GiB", 2 << 30},
+		{"1T", 1 << 40},
+		{"7B", 7},
+	}
+	for _, tt := range tests {
+		got, err := parseSize(tt.s)
+		if err != nil || got != tt.want {
+			t.Errorf("parseSize(%q) = %d, %v, want %d", tt.s, got, err, tt.want)
+		}
+	}
+}
+
+func TestParseSizeInvalid(t *testing.T) {
+	for _, s := range []string{"", "MB", "-1", "ten", "1.5.2KB", "NaN", "Inf", "-Inf", "infMB", "1e30", "1e30TB", "8388608TB"} {
+		if got, err := parseSize(s); err == nil {
+			t.Errorf("parseSize(%q) = %d, want an error", s, got)
+		}
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	tests := []struct {
+		n    int64
+		want string
+	}{
+		{0, "0B"},
+		{1023, "1023B"},
+		{1 << 10, "1KB"},
+		{3 << 19, "1.5MB"},
+		{5 << 30, "5GB"},
+		{1 << 40, "1TB"},
+	}
+	for _, tt := range tests {
+		if got := formatSize(tt.n); got != tt.want {
+			t.Errorf("formatSize(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+)
+
+// what a single-pr run did, filled in as it goes and printed at the end 🧾
+type runSummary struct {
+	sources  []string // copied files
+	dests    []string // where each of them went, in the same order
+	branch   string   // the branch actually used, after -on-exists
+	commit   string   // HEAD once everything was committed
+	prURL    string
+	pushOnly bool // prURL is a compare url, not a pr
+	noPR     bool // committed but not pushed
+}
+
+// prints the summary as aligned label / value lines
+func (s *runSummary) print() {
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 4, 2, ' ', 0)
+	for i, source := range s.sources {
+		fmt.Fprintf(w, "source\t%s\n", source)
+		fmt.Fprintf(w, "dest\t%s\n", s.dests[i])
+	}
+	if s.commit == "" {
+		fmt.Fprintln(w, "commit\tnone, the files are staged on the current branch")
+	} else {
+		fmt.Fprintf(w, "branch\t%s\n", s.branch)
+		fmt.Fprintf(w, "commit\t%s\n", s.commit)
+	}
+	switch {
+	case s.commit == "":
+	case s.noPR:
+		fmt.Fprintln(w, "pr\tnone, not pushed")
+	case s.pushOnly:
+		fmt.Fprintf(w, "compare\t%s\n", s.prURL)
+	default:
+		fmt.Fprintf(w, "pr\t%s\n", s.prURL)
+	}
+	w.Flush()
+	logInfo("\n%s", strings.TrimRight(b.String(), "\n"))
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// the errors a timed out command wraps, so callers can tell the two apart ⏰
+var (
+	errCommandTimeout = errors.New("command timed out")
+	errTotalTimeout   = errors.New("run timed out")
+)
+
+var (
+	// ends when -total-timeout runs out, background if there is none
+	runCtx = context.Background()
+	// limit for each command, 0 for none
+	commandTimeout time.Duration
+	// whether fzf gets commandTimeout too, it's interactive so by default not
+	fzfTimeout bool
+)
+
+// returns the context to run one command in; interactive commands only
+// get the per-command limit when asked for
+func commandContext(interactive bool) (context.Context, context.CancelFunc) {
+	if commandTimeout <= 0 || interactive && !fzfTimeout {
+		return context.WithCancel(runCtx)
+	}
+	return context.WithTimeout(runCtx, commandTimeout)
+}
+
+// turns the error of a command killed by ctx into one saying which limit
+// ran out, leaving other errors as they are
+func timeoutError(ctx context.Context, name string, err error) error {
+	if ctx.Err() == nil {
+		return err
+	}
+	if runCtx.Err() != nil {
+		return fmt.Errorf("%w: -total-timeout ran out during %s", errTotalTimeout, name)
+	}
+	return fmt.Errorf("%w: %s took longer than -command-timeout %s", errCommandTimeout, name, commandTimeout)
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// how long each phase of a run took, for -stats ⏱️
+type phaseStats struct {
+	start time.Time
+
+	mu     sync.Mutex
+	order  []string // phases in the order they first finished
+	totals map[string]time.Duration
+	counts map[string]int
+}
+
+// nil unless -stats is set, so recording is a no-op
+var phaseTimes *phaseStats
+
+func newPhaseStats() *phaseStats {
+	return &phaseStats{
+		start:  time.Now(),
+		totals: make(map[string]time.Duration),
+		counts: make(map[string]int),
+	}
+}
+
+// adds one run of phase that took d
+func (p *phaseStats) add(phase string, d time.Duration) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.totals[phase]; !ok {
+		p.order = append(p.order, phase)
+	}
+	p.totals[phase] += d
+	p.counts[phase]++
+}
+
+// starts timing phase, returning the func that stops it:
+// defer phaseTimes.track("copy")()
+func (p *phaseStats) track(phase string) func() {
+	start := time.Now()
+	return func() {
+		p.add(phase, time.Since(start))
+	}
+}
+
+// names the phase of a command, e.g. "git push" or "gh pr create"
+func commandPhase(name string, args []string) string {
+	n := 1
+	if name == "gh" {
+		n = 2
+	}
+	phase := name
+	for i := 0; i < n && i < len(args) && !strings.HasPrefix(args[i], "-"); i++ {
+		phase += " " + args[i]
+	}
+	return phase
+}
+
+// writes the breakdown with the total since the run started 📊
+func (p *phaseStats) print(w io.Writer) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "PHASE\tRUNS\tTIME")
+	for _, phase := range p.order {
+		fmt.Fprintf(tw, "%s\t%d\t%s\n", phase, p.counts[phase], roundDuration(p.totals[phase]))
+	}
+	fmt.Fprintf(tw, "total\t\t%s\n", roundDuration(time.Since(p.start)))
+	tw.Flush()
+}
+
+// rounds d to a readable precision, e.g. 1.234s or 12.3ms
+func roundDuration(d time.Duration) time.Duration {
+	switch {
+	case d >= time.Second:
+		return d.Round(time.Millisecond)
+	case d >= time.Millisecond:
+		return d.Round(100 * time.Microsecond)
+	default:
+		return d.Round(time.Microsecond)
+	}
+}
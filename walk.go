@@ -0,0 +1,133 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// how many directories the concurrent walker reads at once 🧵
+const walkWorkers = 16
+
+// state shared by the goroutines of one concurrent walk
+type concurrentWalk struct {
+	opts findOptions
+
+	wg  sync.WaitGroup
+	sem chan struct{} // bounds concurrent directory reads
+
+	mu    sync.Mutex
+	files []string
+	err   error // first error, stops the rest of the walk
+}
+
+// walks dir with a bounded pool of readers, for slow (e.g. network) file
+// systems where stat-ing one entry at a time crawls ⚡
+// returns the same sorted relative paths as the sequential walk
+func findFilesConcurrent(dir string, opts findOptions) ([]string, error) {
+	w := &concurrentWalk{
+		opts: opts,
+		sem:  make(chan struct{}, walkWorkers),
+	}
+
+	w.wg.Add(1)
+	go w.walkDir(dir, "", nil)
+	w.wg.Wait()
+
+	if w.err != nil {
+		return nil, w.err
+	}
+	sort.Strings(w.files)
+	return w.files, nil
+}
+
+// records the first error seen
+func (w *concurrentWalk) fail(err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.err == nil {
+		w.err = err
+	}
+}
+
+// reports whether another goroutine already failed
+func (w *concurrentWalk) failed() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.err != nil
+}
+
+func (w *concurrentWalk) addFile(relPath string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.files = append(w.files, relPath)
+}
+
+// reads one directory, queueing its subdirectories as new goroutines
+// visited holds the directories above this one, so symlink loops stop 🔁
+func (w *concurrentWalk) walkDir(dir, relDir string, visited []os.FileInfo) {
+	defer w.wg.Done()
+	if w.failed() {
+		return
+	}
+
+	if w.opts.followSymlinks {
+		info, err := os.Stat(dir)
+		if err != nil {
+			w.fail(err)
+			return
+		}
+		for _, seen := range visited {
+			if os.SameFile(seen, info) {
+				return
+			}
+		}
+		// copy, since sibling goroutines extend the same chain
+		visited = append(visited[:len(visited):len(visited)], info)
+	}
+
+	w.sem <- struct{}{}
+	entries, err := os.ReadDir(dir)
+	<-w.sem
+	if err != nil {
+		w.fail(err)
+		return
+	}
+
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		relPath := filepath.Join(relDir, entry.Name())
+
+		if entry.IsDir() {
+			w.wg.Add(1)
+			go w.walkDir(path, relPath, visited)
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			w.fail(err)
+			return
+		}
+		if info.Mode()&os.ModeSymlink != 0 && w.opts.followSymlinks {
+			// dangling symlinks are still listed and copied as links
+			if target, err := os.Stat(path); err == nil {
+				if target.IsDir() {
+					w.wg.Add(1)
+					go w.walkDir(path, relPath, visited)
+					continue
+				}
+				info = target
+			}
+		}
+
+		if w.opts.keep(relPath, info) {
+			if err := w.opts.limit.add(); err != nil {
+				w.fail(err)
+				return
+			}
+			w.addFile(relPath)
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// how long a new file has to go without changing before -watch picks it
+// up, so bursts of events settle and files still being written finish ⏳
+const watchSettle = 2 * time.Second
+
+// names of temporary or partial files that -watch never picks up 🚧
+var watchIgnorePatterns = []string{"*.tmp", "*.temp", "*.part", "*.partial", "*.crdownload", "*.swp", "*~", ".#*"}
+
+// a new file waiting to settle
+type pendingFile struct {
+	root    string    // search directory it appeared in
+	changed time.Time // when it last changed
+	size    int64     // size when it last changed
+	modTime time.Time // mod time when it last changed
+}
+
+// reports whether name looks like a file that is still being written
+func isPartialFile(name string) bool {
+	for _, pattern := range watchIgnorePatterns {
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// watches dir and every directory below it, since fsnotify only reports
+// the direct children of a watched directory
+func watchTree(watcher *fsnotify.Watcher, dir string) error {
+	return filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if err := watcher.Add(path); err != nil {
+				return fmt.Errorf("failed to watch %s: %w", path, err)
+			}
+		}
+		return nil
+	})
+}
+
+// opens a branch and pr for every file that shows up in the roots until
+// interrupted, like -separate-prs without the selection 👀
+// failures are logged and the watch goes on; the summary of every file
+// handled is printed on the way out
+func watchFiles(roots []string, findOpts findOptions, opts gitOptions, copyOpts copyOptions) error {
+	dir := opts.targetDir
+	baseBranch, err := batchBase(dir, "-watch")
+	if err != nil {
+		return err
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to start the watcher: %w", err)
+	}
+	defer watcher.Close()
+	for _, root := range roots {
+		if err := watchTree(watcher, root); err != nil {
+			return err
+		}
+	}
+
+	// ctrl-c stops the watch rather than the whole process 🛑
+	ctx, stop := signal.NotifyContext(runCtx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	opts.skipBrowse = true
+	findOpts.stats, findOpts.limit = nil, nil
+	labels := rootLabels(roots)
+	pending := make(map[string]*pendingFile)
+	var results []batchResult
+
+	// waits for path, or restarts its wait when it changed since
+	track := func(root, path string, info os.FileInfo) {
+		p, ok := pending[path]
+		if !ok {
+			p = &pendingFile{root: root}
+			pending[path] = p
+		}
+		p.changed, p.size, p.modTime = time.Now(), info.Size(), info.ModTime()
+	}
+
+	// picks up a file that's new, either by itself or inside a new directory
+	created := func(path string) {
+		root, ok := rootOf(roots, path)
+		if !ok {
+			return
+		}
+		info, err := os.Lstat(path)
+		if err != nil {
+			// already gone again
+			return
+		}
+		if !info.IsDir() {
+			if !isPartialFile(info.Name()) {
+				track(root, path, info)
+			}
+			return
+		}
+		// files can land in a new directory before it is watched
+		if err := watchTree(watcher, path); err != nil {
+			logWarning("%v", err)
+		}
+		filepath.WalkDir(path, func(file string, entry fs.DirEntry, err error) error {
+			if err == nil && !entry.IsDir() && !isPartialFile(entry.Name()) {
+				if info, err := entry.Info(); err == nil {
+					track(root, file, info)
+				}
+			}
+			return nil
+		})
+	}
+
+	// opens the pr for a settled file
+	process := func(path string, p *pendingFile, info os.FileInfo) {
+		rel, err := filepath.Rel(p.root, path)
+		if err != nil {
+			return
+		}
+		if !findOpts.keep(rel, info) {
+			logVerbose("skipping %s, filtered out", rel)
+			return
+		}
+
+		file := searchFile{root: p.root, rel: rel, display: rel}
+		if len(roots) > 1 {
+			for i, root := range roots {
+				if root == p.root {
+					file.display = filepath.Join(labels[i], rel)
+				}
+			}
+		}
+		logInfo("new file: %s", file.display)
+		results = append(results, batchFile(opts, copyOpts, file))
+
+		// back to the base branch for the next file
+		if err := runCommand(dir, "git", "checkout", "-f", baseBranch); err != nil {
+			logError("error returning to %s: %v", baseBranch, err)
+		}
+	}
+
+	ticker := time.NewTicker(watchSettle / 4)
+	defer ticker.Stop()
+
+	logInfo("watching %s for new files, ctrl-c to stop 👀", strings.Join(roots, ", "))
+	for {
+		select {
+		case <-ctx.Done():
+			logInfo("stopping the watch")
+			if len(results) > 0 {
+				printBatchSummary(results)
+			}
+			if runCtx.Err() != nil {
+				return fmt.Errorf("%w: -total-timeout ran out during -watch", errTotalTimeout)
+			}
+			return nil
+
+		case err := <-watcher.Errors:
+			logWarning("watch error: %v", err)
+
+		case event := <-watcher.Events:
+			switch {
+			case event.Has(fsnotify.Create):
+				created(event.Name)
+			case event.Has(fsnotify.Write) || event.Has(fsnotify.Chmod):
+				if p, ok := pending[event.Name]; ok {
+					if info, err := os.Lstat(event.Name); err == nil {
+						track(p.root, event.Name, info)
+					}
+				}
+			case event.Has(fsnotify.Remove) || event.Has(fsnotify.Rename):
+				// a rename shows up again as a create of the new name
+				delete(pending, event.Name)
+			}
+
+		case now := <-ticker.C:
+			for path, p := range pending {
+				if ctx.Err() != nil {
+					break
+				}
+				if now.Sub(p.changed) < watchSettle {
+					continue
+				}
+				info, err := os.Lstat(path)
+				if err != nil {
+					delete(pending, path)
+					continue
+				}
+				// some writers (and network file systems) don't send events
+				if info.Size() != p.size || !info.ModTime().Equal(p.modTime) {
+					track(p.root, path, info)
+					continue
+				}
+				delete(pending, path)
+				process(path, p, info)
+			}
+		}
+	}
+}
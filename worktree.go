@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// a temporary worktree that stands in for the target directory 🌳
+type worktree struct {
+	repoDir   string // the original target directory
+	dir       string // root of the temporary worktree
+	targetDir string // the target directory's counterpart inside dir
+}
+
+// creates a detached worktree of targetDir's repo at its current HEAD, so
+// the user's own checkout never changes branch
+func createWorktree(targetDir string) (*worktree, error) {
+	// the target may be a subdirectory of the repo, keep the same spot
+	prefix, err := commandOutput(targetDir, "git", "rev-parse", "--show-prefix")
+	if err != nil {
+		return nil, fmt.Errorf("failed to find repo root: %w", err)
+	}
+
+	dir, err := os.MkdirTemp("", "elf-owl-worktree-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+
+	if err := runCommand(targetDir, "git", "worktree", "add", "--detach", dir, "HEAD"); err != nil {
+		os.RemoveAll(dir)
+		return nil, fmt.Errorf("failed to create worktree: %w", err)
+	}
+
+	logVerbose("using worktree %s", dir)
+	return &worktree{
+		repoDir:   targetDir,
+		dir:       dir,
+		targetDir: filepath.Join(dir, prefix),
+	}, nil
+}
+
+// removes the worktree, even if it has uncommitted changes left over 🧹
+func (w *worktree) remove() {
+	if err := runCommand(w.repoDir, "git", "worktree", "remove", "--force", w.dir); err != nil {
+		logError("error removing worktree %s: %v", w.dir, err)
+	}
+}
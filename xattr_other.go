@@ -0,0 +1,10 @@
+//go:build !(linux || darwin)
+
+package main
+
+import "fmt"
+
+// extended attributes aren't supported here, so -preserve-xattrs only warns
+func copyXattrs(src, dst string) error {
+	return fmt.Errorf("can't preserve extended attributes of %s on this platform", src)
+}